@@ -6,9 +6,11 @@ import (
 	"log"
 	"os"
 	"strings"
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	config "github.com/metgag/koda-weekly10/internals/configs"
+	"github.com/metgag/koda-weekly10/internals/repositories"
 	"github.com/metgag/koda-weekly10/internals/routers"
 )
 
@@ -50,6 +52,19 @@ func main() {
 	}
 	defer rdb.Close()
 
-	router := routers.InitRouter(dbpool, rdb)
+	// rebuild popularity buckets and the autocomplete index before serving so
+	// no sale or movie change made meanwhile is overwritten by the rebuild
+	mr := repositories.NewMovieRepository(dbpool, rdb)
+	if err := mr.RebuildPopularity(context.Background()); err != nil {
+		log.Printf("unable to rebuild popularity: %s\n", err)
+	}
+	if err := mr.RebuildSuggestIndex(context.Background()); err != nil {
+		log.Printf("unable to rebuild suggestion index: %s\n", err)
+	}
+
+	// the word list is read once and shared by every review handler
+	profanity := config.InitProfanityFilter()
+
+	router := routers.InitRouter(dbpool, rdb, profanity)
 	router.Run(":6011")
 }
@@ -0,0 +1,2 @@
+ALTER TABLE schedule
+    ADD COLUMN price INTEGER NOT NULL DEFAULT 50000;
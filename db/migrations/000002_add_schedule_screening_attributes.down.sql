@@ -0,0 +1,6 @@
+ALTER TABLE schedule
+    DROP COLUMN subtitle_language,
+    DROP COLUMN audio_language,
+    DROP COLUMN format;
+
+DROP TABLE IF EXISTS screen_formats;
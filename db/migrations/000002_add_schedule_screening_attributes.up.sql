@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS screen_formats (
+    code VARCHAR(10) PRIMARY KEY,
+    surcharge INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO screen_formats (code, surcharge)
+VALUES
+    ('2D', 0),
+    ('3D', 15000),
+    ('IMAX', 35000),
+    ('4DX', 45000)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE schedule
+    ADD COLUMN format VARCHAR(10) NOT NULL DEFAULT '2D' REFERENCES screen_formats(code),
+    ADD COLUMN audio_language VARCHAR(20) NOT NULL DEFAULT 'en',
+    ADD COLUMN subtitle_language VARCHAR(20);
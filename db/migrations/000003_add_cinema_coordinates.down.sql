@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_cinema_tayang_coordinates;
+
+ALTER TABLE cinema_tayang
+    DROP COLUMN longitude,
+    DROP COLUMN latitude,
+    DROP COLUMN address;
@@ -0,0 +1,7 @@
+ALTER TABLE cinema_tayang
+    ADD COLUMN address TEXT,
+    ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180);
+
+CREATE INDEX IF NOT EXISTS idx_cinema_tayang_coordinates
+    ON cinema_tayang (latitude, longitude);
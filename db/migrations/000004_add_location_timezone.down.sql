@@ -0,0 +1,2 @@
+ALTER TABLE lokasi_tayang
+    DROP COLUMN timezone;
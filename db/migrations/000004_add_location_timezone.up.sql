@@ -0,0 +1,2 @@
+ALTER TABLE lokasi_tayang
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_casts_name_trgm;
+DROP INDEX IF EXISTS idx_directors_name_trgm;
+DROP INDEX IF EXISTS idx_movies_title_trgm;
+DROP INDEX IF EXISTS idx_movies_search_document;
+
+ALTER TABLE movies
+    DROP COLUMN search_document;
@@ -0,0 +1,22 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE movies
+    ADD COLUMN search_document TSVECTOR;
+
+-- weights: title A, director B, casts C, overview D
+UPDATE movies m
+SET search_document =
+    setweight(to_tsvector('simple', coalesce(m.title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce((SELECT d.name FROM directors d WHERE d.id = m.director_id), '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce((
+        SELECT string_agg(c.name, ' ')
+        FROM movies_casts mc
+        JOIN casts c ON c.id = mc.cast_id
+        WHERE mc.movie_id = m.id
+    ), '')), 'C') ||
+    setweight(to_tsvector('simple', coalesce(m.overview, '')), 'D');
+
+CREATE INDEX IF NOT EXISTS idx_movies_search_document ON movies USING GIN (search_document);
+CREATE INDEX IF NOT EXISTS idx_movies_title_trgm ON movies USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_directors_name_trgm ON directors USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_casts_name_trgm ON casts USING GIN (name gin_trgm_ops);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notifications;
+
+ALTER TABLE schedule
+    DROP COLUMN cancelled_at;
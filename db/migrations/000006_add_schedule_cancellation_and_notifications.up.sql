@@ -0,0 +1,14 @@
+ALTER TABLE schedule
+    ADD COLUMN cancelled_at TIMESTAMPTZ;
+
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id INT REFERENCES orders(id) ON DELETE SET NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    read_at TIMESTAMPTZ
+);
+
+CREATE INDEX notifications_user_id_idx ON notifications (user_id, created_at DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS movies_external_id_key;
+
+ALTER TABLE movies
+    DROP COLUMN synced_at,
+    DROP COLUMN external_id,
+    DROP COLUMN external_provider;
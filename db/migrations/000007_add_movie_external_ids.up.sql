@@ -0,0 +1,8 @@
+ALTER TABLE movies
+    ADD COLUMN external_provider VARCHAR(32),
+    ADD COLUMN external_id VARCHAR(64),
+    ADD COLUMN synced_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX movies_external_id_key
+    ON movies (external_provider, external_id)
+    WHERE external_id IS NOT NULL AND deleted_at IS NULL;
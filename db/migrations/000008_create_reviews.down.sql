@@ -0,0 +1,5 @@
+ALTER TABLE movies
+    DROP COLUMN rating_count,
+    DROP COLUMN rating_avg;
+
+DROP TABLE IF EXISTS reviews;
@@ -0,0 +1,17 @@
+CREATE TABLE reviews (
+    id SERIAL PRIMARY KEY,
+    movie_id INT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (movie_id, user_id)
+);
+
+CREATE INDEX reviews_movie_id_idx ON reviews (movie_id, created_at DESC);
+
+-- aggregate kept on the movie so lists can show and sort by it cheaply
+ALTER TABLE movies
+    ADD COLUMN rating_avg REAL NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0;
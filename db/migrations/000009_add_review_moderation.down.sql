@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS review_reports;
+
+DROP INDEX IF EXISTS reviews_status_idx;
+
+ALTER TABLE reviews
+    DROP COLUMN moderated_by,
+    DROP COLUMN moderated_at,
+    DROP COLUMN flagged_words,
+    DROP COLUMN status;
@@ -0,0 +1,18 @@
+ALTER TABLE reviews
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'approved'
+        CHECK (status IN ('pending', 'approved', 'rejected')),
+    ADD COLUMN flagged_words TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN moderated_at TIMESTAMPTZ,
+    ADD COLUMN moderated_by INT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX reviews_status_idx ON reviews (status, created_at);
+
+CREATE TABLE review_reports (
+    id SERIAL PRIMARY KEY,
+    review_id INT NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    resolved_at TIMESTAMPTZ,
+    UNIQUE (review_id, user_id)
+);
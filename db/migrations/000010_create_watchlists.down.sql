@@ -0,0 +1,4 @@
+ALTER TABLE notifications
+    DROP COLUMN movie_id;
+
+DROP TABLE IF EXISTS watchlists;
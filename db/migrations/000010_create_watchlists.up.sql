@@ -0,0 +1,11 @@
+CREATE TABLE watchlists (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX watchlists_movie_id_idx ON watchlists (movie_id);
+
+ALTER TABLE notifications
+    ADD COLUMN movie_id INT REFERENCES movies(id) ON DELETE CASCADE;
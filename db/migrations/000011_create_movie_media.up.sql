@@ -0,0 +1,19 @@
+CREATE TABLE movie_media (
+    id SERIAL PRIMARY KEY,
+    movie_id INT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL
+        CHECK (kind IN ('poster', 'backdrop', 'still', 'trailer')),
+    url TEXT NOT NULL,
+    provider VARCHAR(16)
+        CHECK (provider IN ('youtube', 'vimeo', 'file')),
+    language VARCHAR(8),
+    caption VARCHAR(255),
+    position INT NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX movie_media_movie_id_idx ON movie_media (movie_id, kind, position);
+
+-- one primary item per kind
+CREATE UNIQUE INDEX movie_media_primary_idx ON movie_media (movie_id, kind) WHERE is_primary;
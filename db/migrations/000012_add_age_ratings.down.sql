@@ -0,0 +1,8 @@
+ALTER TABLE orders
+    DROP COLUMN age_check_overridden_by;
+
+ALTER TABLE personal_info
+    DROP COLUMN birthdate;
+
+ALTER TABLE movies
+    DROP COLUMN age_rating;
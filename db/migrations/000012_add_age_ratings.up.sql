@@ -0,0 +1,11 @@
+-- LSF classification: SU (all ages), 13+, 17+, 21+
+ALTER TABLE movies
+    ADD COLUMN age_rating VARCHAR(3) NOT NULL DEFAULT 'SU'
+        CHECK (age_rating IN ('SU', '13+', '17+', '21+'));
+
+ALTER TABLE personal_info
+    ADD COLUMN birthdate DATE;
+
+-- set when an admin sold tickets without the age check
+ALTER TABLE orders
+    ADD COLUMN age_check_overridden_by INT REFERENCES users(id) ON DELETE SET NULL;
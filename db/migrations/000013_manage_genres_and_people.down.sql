@@ -0,0 +1,7 @@
+-- people added for directors stay in casts, they are not credited anywhere
+DROP INDEX IF EXISTS idx_directors_person_id;
+
+ALTER TABLE directors
+    DROP COLUMN IF EXISTS person_id;
+
+DROP INDEX IF EXISTS idx_genres_name_lower;
@@ -0,0 +1,22 @@
+-- admins add genres, new ids continue after the seeded TMDB ones
+CREATE SEQUENCE IF NOT EXISTS genres_id_seq OWNED BY genres.id;
+ALTER TABLE genres ALTER COLUMN id SET DEFAULT nextval('genres_id_seq');
+SELECT setval('genres_id_seq', COALESCE((SELECT MAX(id) FROM genres), 0) + 1, false);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_genres_name_lower ON genres (LOWER(genre_name));
+
+-- casts holds every person, a director links to the person they are so one
+-- page lists the movies they acted in and directed
+INSERT INTO casts (name)
+SELECT name FROM directors
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE directors
+    ADD COLUMN person_id INT REFERENCES casts(id) ON DELETE SET NULL;
+
+UPDATE directors d
+SET person_id = c.id
+FROM casts c
+WHERE c.name = d.name;
+
+CREATE INDEX IF NOT EXISTS idx_directors_person_id ON directors (person_id);
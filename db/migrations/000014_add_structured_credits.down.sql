@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS movie_crew;
+
+ALTER TABLE movies_casts
+    DROP COLUMN IF EXISTS billing_order,
+    DROP COLUMN IF EXISTS character_name;
@@ -0,0 +1,20 @@
+ALTER TABLE movies_casts
+    ADD COLUMN character_name VARCHAR(255),
+    ADD COLUMN billing_order INT;
+
+-- person_id is a person of casts, movies.director_id stays the first director
+CREATE TABLE movie_crew (
+    movie_id  INT         NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    person_id INT         NOT NULL REFERENCES casts(id) ON DELETE CASCADE,
+    job       VARCHAR(20) NOT NULL CHECK (job IN ('director', 'writer', 'producer', 'composer')),
+    position  INT         NOT NULL DEFAULT 0,
+    PRIMARY KEY (movie_id, person_id, job)
+);
+
+CREATE INDEX idx_movie_crew_person_id ON movie_crew (person_id);
+
+INSERT INTO movie_crew (movie_id, person_id, job)
+SELECT m.id, d.person_id, 'director'
+FROM movies m
+JOIN directors d ON d.id = m.director_id
+WHERE d.person_id IS NOT NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE collections (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    overview TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX collections_name_key ON collections (LOWER(name));
+
+-- a movie belongs to one collection at most, position orders the series
+CREATE TABLE collection_movies (
+    collection_id INT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    movie_id INT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (collection_id, movie_id)
+);
+
+CREATE UNIQUE INDEX collection_movies_movie_id_key ON collection_movies (movie_id);
@@ -0,0 +1,17 @@
+-- movies and genres hold the default language, a NULL field of a
+-- translation falls back to it
+CREATE TABLE movie_translations (
+    movie_id INT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    language VARCHAR(8) NOT NULL,
+    title VARCHAR(255),
+    overview TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (movie_id, language)
+);
+
+CREATE TABLE genre_translations (
+    genre_id INT NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    language VARCHAR(8) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    PRIMARY KEY (genre_id, language)
+);
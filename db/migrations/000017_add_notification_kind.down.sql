@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS notifications_order_id_kind_idx;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS kind;
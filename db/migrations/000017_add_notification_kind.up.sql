@@ -0,0 +1,11 @@
+ALTER TABLE notifications
+    ADD COLUMN kind VARCHAR(30);
+
+UPDATE notifications SET kind = 'schedule_cancelled' WHERE title = 'Showtime cancelled';
+UPDATE notifications SET kind = 'tickets_open' WHERE title = 'Tickets are open';
+UPDATE notifications SET kind = 'general' WHERE kind IS NULL;
+
+ALTER TABLE notifications
+    ALTER COLUMN kind SET NOT NULL;
+
+CREATE INDEX notifications_order_id_kind_idx ON notifications (order_id, kind);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS review_reports_user_id_created_at_idx;
+DROP INDEX IF EXISTS review_reports_open_idx;
+
+-- keep the latest report of each user on a review
+DELETE FROM review_reports r
+USING review_reports newer
+WHERE newer.review_id = r.review_id AND newer.user_id = r.user_id AND newer.id > r.id;
+
+ALTER TABLE review_reports
+    ADD CONSTRAINT review_reports_review_id_user_id_key UNIQUE (review_id, user_id);
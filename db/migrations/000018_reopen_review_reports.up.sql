@@ -0,0 +1,8 @@
+-- a user may report a review again once a moderator resolved their report
+ALTER TABLE review_reports
+    DROP CONSTRAINT IF EXISTS review_reports_review_id_user_id_key;
+
+CREATE UNIQUE INDEX review_reports_open_idx ON review_reports (review_id, user_id)
+    WHERE resolved_at IS NULL;
+
+CREATE INDEX review_reports_user_id_created_at_idx ON review_reports (user_id, created_at);
@@ -15,127 +15,79 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/movies": {
-            "post": {
+        "/admin/casts": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new movie with poster and backdrop upload, genres and casts as JSON arrays.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "sorted by name so duplicates like \"Tom Hanks\" and \"tom hanks\" sit together, movie_count counts their credits",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Create a new movie",
+                "summary": "list directors or cast members (admin)",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "id",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movie title",
-                        "name": "title",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Backdrop image file",
-                        "name": "backdrop_path",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Poster image file",
-                        "name": "poster_path",
-                        "in": "formData"
-                    },
                     {
                         "type": "string",
-                        "description": "Release date in YYYY-MM-DD format",
-                        "name": "release_date",
-                        "in": "formData",
-                        "required": true
+                        "description": "name contains",
+                        "name": "q",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Runtime in minutes",
-                        "name": "runtime",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movie overview",
-                        "name": "overview",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Director's full name",
-                        "name": "director_name",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Popularity score (e.g. 78.5)",
-                        "name": "popularity",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "JSON array of genre IDs as string: [12,14,18]",
-                        "name": "genres",
-                        "in": "formData"
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "JSON array of cast IDs as string: [1,2,3]",
-                        "name": "casts",
-                        "in": "formData"
+                        "type": "integer",
+                        "example": 20,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created movie",
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateMovieResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request, e.g. invalid input or file upload error",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CreateMovieResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PersonSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
-                        "description": "Internal server error, e.g. binding failure",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.CreateMovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/admin/movies/": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "complete list of movies from the database",
                 "consumes": [
                     "application/json"
                 ],
@@ -145,45 +97,65 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "get all movies (admin)",
+                "summary": "add a director or cast member (admin)",
+                "parameters": [
+                    {
+                        "description": "name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PersonBody"
+                        }
+                    }
+                ],
                 "responses": {
-                    "200": {
-                        "description": "list of all movies",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "name is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/admin/movies/{id}": {
+        "/admin/casts/{id}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "soft delete a movie from the database using id",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "only people no movie credits can be deleted, merge credited duplicates instead",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "delete a movie w/ ID",
+                "summary": "delete a director or cast member (admin)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "movie ID",
+                        "description": "director or cast ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -191,27 +163,33 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie deleted successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteMovieResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid movie ID format",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteMovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Movie not found",
+                        "description": "person not found",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteMovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "person is still credited",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Server error while deleting movie",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteMovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
@@ -222,9 +200,9 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "update a movie's details",
+                "description": "a director and the cast member they are linked to are renamed together",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
@@ -232,77 +210,67 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "update a movie w/ ID",
+                "summary": "rename a director or cast member (admin)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "movie ID to be updated",
+                        "description": "director or cast ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "movie title",
-                        "name": "title",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "movie description",
-                        "name": "overview",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "movie duration (minutes)",
-                        "name": "runtime",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "new backdrop file",
-                        "name": "backdrop_path",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "new poster file",
-                        "name": "poster_path",
-                        "in": "formData"
+                        "description": "new name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PersonBody"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "movie updated successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateMovieResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid input or file",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateMovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "movie not found",
+                        "description": "person not found",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateMovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "name is already taken, merge them instead",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateMovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/login": {
+        "/admin/casts/{id}/merge": {
             "post": {
-                "description": "Authenticates user by verifying email and password. Returns a JWT access token upon success.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "every credit of ids moves to the person of the path, ids are deleted afterwards",
                 "consumes": [
                     "application/json"
                 ],
@@ -310,73 +278,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "User login",
+                "summary": "merge duplicate directors or cast members (admin)",
                 "parameters": [
                     {
-                        "description": "User login request body",
-                        "name": "request",
+                        "type": "integer",
+                        "description": "director or cast ID kept",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "IDs merged into it",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Login"
+                            "$ref": "#/definitions/models.PeopleMergeBody"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful with JWT token",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid email or password",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "person not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/logout": {
-            "delete": {
+        "/admin/cinemas/{cinema_id}": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Logout user with blacklist to redis",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Logout user",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.LogoutResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Register a new user with email and password",
+                "description": "latitude and longitude are set together",
                 "consumes": [
                     "application/json"
                 ],
@@ -384,84 +342,121 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Register new user",
+                "summary": "Update cinema address and coordinates",
                 "parameters": [
                     {
-                        "description": "User registration request body",
+                        "type": "integer",
+                        "description": "Cinema ID",
+                        "name": "cinema_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "address, latitude, longitude",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Register"
+                            "$ref": "#/definitions/models.UpdateCinemaBody"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully registered",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
-                    "409": {
-                        "description": "Email already registered",
+                    "400": {
+                        "description": "Invalid body",
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cinema not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/cinemas/schedules": {
+        "/admin/collections": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all available cinema schedules from the server",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cinemas"
+                    "admin"
+                ],
+                "summary": "list collections (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get cinema schedules",
                 "responses": {
                     "200": {
-                        "description": "Schedules fetched successfully or no schedules available",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ScheduleResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CollectionSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
-                        "description": "Internal server error while fetching the schedule",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ScheduleResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/cinemas/{schedule_id}/seats": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all available seats for a specific cinema schedule",
                 "consumes": [
                     "application/json"
                 ],
@@ -469,117 +464,106 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cinemas"
+                    "admin"
                 ],
-                "summary": "Get available seats",
+                "summary": "add a collection (admin)",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "The ID of the cinema schedule",
-                        "name": "schedule_id",
-                        "in": "path",
-                        "required": true
+                        "description": "name and overview",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionBody"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Available seats retrieved successfully or no seats available",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.AvailSeatsResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid schedule ID format",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.AvailSeatsResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "name is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while fetching available seats",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.AvailSeatsResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/movies/": {
-            "get": {
-                "consumes": [
-                    "application/json"
+        "/admin/collections/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "the movies of the collection are kept",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "admin"
                 ],
-                "summary": "get movie with filter by name and genre with pagination",
+                "summary": "delete a collection (admin)",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "example": "pulp",
-                        "description": "search title by q",
-                        "name": "q",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "example": 1,
-                        "description": "page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "action",
-                        "description": "genre",
-                        "name": "genre",
-                        "in": "query"
+                        "description": "collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/movies/popular": {
-            "get": {
-                "description": "get list of movies where popularity higher than 40",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "movies"
-                ],
-                "summary": "get popular movies handler func",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/movies/upcoming": {
-            "get": {
-                "description": "Get list of movies with release date later than the current date",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the overview is kept when not given",
                 "consumes": [
                     "application/json"
                 ],
@@ -587,34 +571,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "admin"
+                ],
+                "summary": "rename a collection (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "name and overview",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionBody"
+                        }
+                    }
                 ],
-                "summary": "Get upcoming movies",
                 "responses": {
                     "200": {
-                        "description": "Upcoming movies fetched successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "No upcoming movies found",
+                        "description": "collection not found",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "name is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/movies/{id}": {
-            "get": {
-                "description": "get movie detail based from id",
+        "/admin/collections/{id}/movies": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ids replace the movies of the collection in series order, an empty list empties it. a movie belongs to one collection at most",
                 "consumes": [
                     "application/json"
                 ],
@@ -622,122 +641,202 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "admin"
                 ],
-                "summary": "get movie detail handler func",
+                "summary": "set the movies of a collection (admin)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "movie ID",
+                        "description": "collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "movie IDs in series order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionMoviesBody"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "movie detail data",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid movie id",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "movie not found",
+                        "description": "collection or movie not found",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "movie already belongs to another collection",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/movies/{id}/schedule": {
+        "/admin/directors": {
             "get": {
-                "description": "Retrieve available movie schedules based on movie ID, date, time, and location.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "sorted by name so duplicates like \"Tom Hanks\" and \"tom hanks\" sit together, movie_count counts their credits",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "schedule"
+                    "admin"
                 ],
-                "summary": "Get movie schedule filter",
+                "summary": "list directors or cast members (admin)",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by date (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query",
-                        "required": true
+                        "description": "name contains",
+                        "name": "q",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Filter by time (HHMM in 24h format, e.g. 2030)",
-                        "name": "time",
-                        "in": "query",
-                        "required": true
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Filter by location ID",
-                        "name": "location",
-                        "in": "query",
-                        "required": true
+                        "example": 20,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieScheduleFilterResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "No matching schedule found",
-                        "schema": {
-                            "$ref": "#/definitions/models.MovieScheduleFilterResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PersonSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieScheduleFilterResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/movies/{id}/schedules": {
-            "get": {
-                "description": "movie schedules (date, time, location, cinema) for a given movie ID",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "schedule"
-                ],
-                "summary": "get movie schedules by ID",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "add a director or cast member (admin)",
+                "parameters": [
+                    {
+                        "description": "name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PersonBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "name is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/directors/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "only people no movie credits can be deleted, merge credited duplicates instead",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "delete a director or cast member (admin)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "director or cast ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -745,28 +844,44 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Schedules retrieved successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieSchedulesResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "person not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "person is still credited",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.MovieSchedulesResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/orders": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "create a new user's order",
+                "description": "a director and the cast member they are linked to are renamed together",
                 "consumes": [
                     "application/json"
                 ],
@@ -774,56 +889,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "create user order handler func",
+                "summary": "rename a director or cast member (admin)",
                 "parameters": [
                     {
-                        "description": "Order body",
-                        "name": "order",
+                        "type": "integer",
+                        "description": "director or cast ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new name",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CinemaOrder"
+                            "$ref": "#/definitions/models.PersonBody"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order created successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized: invalid or missing token",
+                    "404": {
+                        "description": "person not found",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "name is already taken, merge them instead",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users": {
-            "get": {
+        "/admin/directors/{id}/merge": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get user's profile details",
+                "description": "every credit of ids moves to the person of the path, ids are deleted afterwards",
                 "consumes": [
                     "application/json"
                 ],
@@ -831,155 +959,174 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admin"
+                ],
+                "summary": "merge duplicate directors or cast members (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "director or cast ID kept",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "IDs merged into it",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PeopleMergeBody"
+                        }
+                    }
                 ],
-                "summary": "get user profile info based from ID",
                 "responses": {
                     "200": {
-                        "description": "User profile retrieved successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserinfResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized or invalid token",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.UserinfResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "person not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.UserinfResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/admin/genres": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update user's profile details via multipart form",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admin"
                 ],
-                "summary": "Update user profile info by ID",
+                "summary": "add a genre (admin)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "First name",
-                        "name": "first_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Last name",
-                        "name": "last_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Phone number (e.g., 08667728761)",
-                        "name": "phone_number",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "description": "User's point (e.g., 4.8)",
-                        "name": "point_count",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Avatar image file",
-                        "name": "avatar",
-                        "in": "formData"
+                        "description": "genre name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GenreBody"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User profile updated successfully",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID or no user found",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "name is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Server error while updating profile",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/orders": {
-            "get": {
+        "/admin/genres/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get user's order watch histories",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "only genres no movie uses can be deleted",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admin"
+                ],
+                "summary": "delete a genre (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "get user order history info based from ID",
                 "responses": {
                     "200": {
-                        "description": "User order history retrieved successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.HistoryResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
-                    "204": {
-                        "description": "No order history found for user",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.HistoryResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized or invalid token",
+                    "404": {
+                        "description": "genre not found",
                         "schema": {
-                            "$ref": "#/definitions/models.HistoryResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "genre is still used by movies",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.HistoryResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/users/password": {
+            },
             "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "allows user's to update their password",
                 "consumes": [
                     "application/json"
                 ],
@@ -987,105 +1134,5291 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admin"
                 ],
-                "summary": "update user's password",
+                "summary": "rename a genre (admin)",
                 "parameters": [
                     {
-                        "description": "new password JSON body",
-                        "name": "request",
+                        "type": "integer",
+                        "description": "genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new name",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PasswordBody"
+                            "$ref": "#/definitions/models.GenreBody"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "password updated successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.EditPasswordResponse"
+                            "$ref": "#/definitions/models.FulfilledResponse"
                         }
                     },
                     "400": {
-                        "description": "bad request - validation failed or user not found",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.EditPasswordResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "server error while updating password",
+                    "404": {
+                        "description": "genre not found",
                         "schema": {
-                            "$ref": "#/definitions/models.EditPasswordResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    }
+                    },
+                    "409": {
+                        "description": "name is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/genres/{id}/translations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "list the translations of a genre (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.FulfilledResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.GenreTranslation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "genre not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/genres/{id}/translations/{lang}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the translated name is shown for lang and accepted by the genre filters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "translate a genre (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "translated name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GenreTranslationBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "genre not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "delete a translation of a genre (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "genre ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "translation not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/locations/{location_id}/timezone": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the IANA timezone used to read the showtimes of a location",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set location timezone",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "IANA timezone",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LocationTimezoneBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid timezone",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Location not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new movie with poster and backdrop upload, genres and casts as JSON arrays.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a new movie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movie title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Backdrop image file",
+                        "name": "backdrop_path",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Poster image file",
+                        "name": "poster_path",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Release date in YYYY-MM-DD format",
+                        "name": "release_date",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Runtime in minutes",
+                        "name": "runtime",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movie overview",
+                        "name": "overview",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "SU",
+                            "13+",
+                            "17+",
+                            "21+"
+                        ],
+                        "type": "string",
+                        "description": "LSF age rating, SU when empty",
+                        "name": "age_rating",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Director's full name, required without a crew director in credits",
+                        "name": "director_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Popularity score (e.g. 78.5)",
+                        "name": "popularity",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of genre IDs as string: [12,14,18]",
+                        "name": "genres",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of cast IDs as string: [1,2,3]",
+                        "name": "casts",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON models.CreditsBody, cast with characters and billing order and crew with jobs, wins over casts and director_name",
+                        "name": "credits",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Screen format of the created schedules (2D, 3D, IMAX, 4DX)",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Audio language of the created schedules",
+                        "name": "audio_language",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subtitle language of the created schedules",
+                        "name": "subtitle_language",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Base ticket price of the created schedules",
+                        "name": "price",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created movie",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateMovieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, e.g. invalid input or file upload error",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateMovieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, e.g. binding failure",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateMovieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "complete list of movies from the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "get all movies (admin)",
+                "responses": {
+                    "200": {
+                        "description": "list of all movies",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/from-provider/{external_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "fetch overview, runtime, director, casts, genres and images of external_id and create the movie, the provider id is kept for later re-syncs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "create a movie from the metadata provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "680",
+                        "description": "provider movie id",
+                        "name": "external_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "unknown external id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "already imported",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "incomplete metadata",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "CSV needs a header line, JSON an array of objects. columns: title, overview, runtime, release_date, director, casts, genres, poster_path, backdrop_path. casts and genres are comma separated, posters are optional URLs or filenames. valid rows are inserted in one transaction, invalid ones are reported per line",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "import movies from a CSV or JSON file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "catalogue .csv or .json, max 2MB",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only validate the rows",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry run report",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieImportResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "movies imported",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "unreadable file or no valid rows",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieImportResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieImportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/lookup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "search the external catalogue (TMDB) for candidates to create movies from",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "look movies up in the metadata provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "pulp fiction",
+                        "description": "movie title",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.FulfilledResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/pkg.MetadataCandidate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/purge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "purge a trashed movie by ID, or several with POST /admin/movies/purge and a list of ids. refused when any of them has orders",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "permanently delete trashed movies",
+                "parameters": [
+                    {
+                        "description": "ids for bulk purge",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieIDsBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "movie has orders",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "restore a movie by ID, or several with POST /admin/movies/restore and a list of ids",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "restore soft-deleted movies",
+                "parameters": [
+                    {
+                        "description": "ids for bulk restore",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieIDsBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "movies in the trash, newest deletion first, has_orders tells whether a purge would be refused",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "get soft-deleted movies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TrashedMovie"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "soft delete a movie from the database using id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "delete a movie w/ ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Movie deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteMovieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid movie ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteMovieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteMovieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while deleting movie",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteMovieResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update a movie's details",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "update a movie w/ ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "movie title",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "movie description",
+                        "name": "overview",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "movie duration (minutes)",
+                        "name": "runtime",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "SU",
+                            "13+",
+                            "17+",
+                            "21+"
+                        ],
+                        "type": "string",
+                        "description": "LSF age rating",
+                        "name": "age_rating",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "director's full name",
+                        "name": "director_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated cast names, replaces the cast",
+                        "name": "casts",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated genres, replaces the genres",
+                        "name": "genres",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON models.CreditsBody, cast with characters and billing order and crew with jobs. each list given replaces its credits, cast wins over casts",
+                        "name": "credits",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "new backdrop file",
+                        "name": "backdrop_path",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "new poster file",
+                        "name": "poster_path",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "movie updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMovieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input or file",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMovieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMovieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMovieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/media": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "upload a poster, backdrop or still, or link one by url. trailers take a video url, the provider is guessed from it when not given. a primary poster or backdrop replaces the one shown in listings",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "add a gallery item (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "poster",
+                            "backdrop",
+                            "still",
+                            "trailer"
+                        ],
+                        "type": "string",
+                        "description": "media kind",
+                        "name": "kind",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "image file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "image or video url",
+                        "name": "url",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "youtube",
+                            "vimeo",
+                            "file"
+                        ],
+                        "type": "string",
+                        "description": "trailer provider",
+                        "name": "provider",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "en",
+                        "description": "language code",
+                        "name": "language",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "caption",
+                        "name": "caption",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "make it the primary of its kind",
+                        "name": "primary",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/media/order": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "items are placed in the order of ids, listed per kind",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "reorder gallery items (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "media ids in their new order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MediaOrderBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "media not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/media/{media_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the next item of the same kind becomes primary when the primary is deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "delete a gallery item (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "media ID",
+                        "name": "media_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "media not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/media/{media_id}/primary": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the item becomes the primary of its kind, a primary poster or backdrop replaces the one shown in listings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "mark a gallery item primary (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "media ID",
+                        "name": "media_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "media not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/purge": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "purge a trashed movie by ID, or several with POST /admin/movies/purge and a list of ids. refused when any of them has orders",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "permanently delete trashed movies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ids for bulk purge",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieIDsBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "movie has orders",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "restore a movie by ID, or several with POST /admin/movies/restore and a list of ids",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "restore soft-deleted movies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ids for bulk restore",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieIDsBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieBulkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/translations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "list the translations of a movie (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.FulfilledResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.MovieTranslation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/movies/{id}/translations/{lang}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "sets the title and overview shown for lang, fields left out keep their translation or the default language",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "translate a movie (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "translated title and overview",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieTranslationBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "delete a translation of a movie (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "translation not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "book on behalf of user_id, or the admin when omitted. the buyer must meet the age rating of the movie unless override_age_check is set, overrides are recorded on the order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "create a box office order (admin)",
+                "parameters": [
+                    {
+                        "description": "Order body",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BoxOfficeOrderBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or schedule closed",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Buyer fails the age check",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown user_id",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "reviews by status, oldest first, with flagged words and open reports",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "review moderation queue",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "review status, default pending",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ModerationReview"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{review_id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "publish a held review and resolve its reports",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "approve a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{review_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "hide a review for good and resolve its reports",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "reject a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/schedules": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "create schedules of a movie for every given location (and its cinemas) and time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "create movie schedules",
+                "parameters": [
+                    {
+                        "description": "schedule body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateScheduleBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid body or unknown format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/schedules/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update format, audio \u0026 subtitle language or base price of a schedule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "update a schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateScheduleBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid body or unknown format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "schedule not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticates user by verifying email and password. Returns a JWT access token upon success.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "User login request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Login"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful with JWT token",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid email or password",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Logout user with blacklist to redis",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogoutResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "User registration request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Register"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully registered",
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already registered",
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas": {
+            "get": {
+                "description": "Retrieve all cinemas along with the cities they screen in",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get cinemas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/detail/{cinema_id}": {
+            "get": {
+                "description": "Retrieve a cinema and the cities it screens in",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get cinema detail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cinema ID",
+                        "name": "cinema_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cinema ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cinema not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/nearby": {
+            "get": {
+                "description": "Retrieve cinemas within a radius ordered by distance, optionally with today's remaining showtimes of a movie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get nearby cinemas",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "example": -6.5971,
+                        "description": "Latitude",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "example": 106.806,
+                        "description": "Longitude",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "example": 5,
+                        "description": "Radius in km, default 10",
+                        "name": "radius",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Include today's remaining showtimes of this movie",
+                        "name": "movie_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid coordinates, radius or movie_id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{schedule_id}/seats": {
+            "get": {
+                "description": "Retrieve all available seats for a specific cinema schedule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get available seats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The ID of the cinema schedule",
+                        "name": "schedule_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Available seats retrieved successfully or no seats available",
+                        "schema": {
+                            "$ref": "#/definitions/models.AvailSeatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid schedule ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.AvailSeatsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Schedule cancelled or not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.AvailSeatsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching available seats",
+                        "schema": {
+                            "$ref": "#/definitions/models.AvailSeatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}": {
+            "get": {
+                "description": "the movies of a franchise in series order, now_showing and next_showtime tell which can be booked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "get a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.FulfilledResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "$ref": "#/definitions/models.Collection"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/": {
+            "get": {
+                "description": "q searches title, director, cast and overview, results are ordered by relevance and highlighted unless sort is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "get movie with filter, sort and pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "pulp",
+                        "description": "search title, director, cast \u0026 overview by q",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 12,
+                        "description": "items per page, max 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "action",
+                        "description": "genre",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "action,drama",
+                        "description": "comma separated genres",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "match any or all genres",
+                        "name": "genre_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1990,
+                        "description": "release year from",
+                        "name": "year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 2010,
+                        "description": "release year to",
+                        "name": "year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 90,
+                        "description": "minimum runtime in minutes",
+                        "name": "runtime_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 150,
+                        "description": "maximum runtime in minutes",
+                        "name": "runtime_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Jakarta",
+                        "description": "only movies now showing in city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "release_date",
+                            "popularity",
+                            "title",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "sort by",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.MovieFilter"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.MoviesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/now-showing": {
+            "get": {
+                "description": "Get movies with at least one upcoming showtime, optionally in a city",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get now showing movies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "Jakarta",
+                        "description": "city of the showtimes",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Now showing movies fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.MoviesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No movies showing",
+                        "schema": {
+                            "$ref": "#/definitions/models.MoviesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MoviesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/popular": {
+            "get": {
+                "description": "get movies ranked by tickets sold in the last window days, recent sales weigh more. popularity holds the score",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "get popular movies handler func",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 7,
+                        "description": "days of sales counted, 1-30",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid window",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/suggest": {
+            "get": {
+                "description": "returns the 10 most popular completions of q from the redis prefix index",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "autocomplete movie titles, directors and casts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "pul",
+                        "description": "text typed so far",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuggestionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/upcoming": {
+            "get": {
+                "description": "Get list of movies releasing after today that have no showtime yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get upcoming movies",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upcoming movies fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No upcoming movies found",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}": {
+            "get": {
+                "description": "get movie detail based from id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "get movie detail handler func",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "movie detail data",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid movie id",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/reviews": {
+            "get": {
+                "description": "reviews of a movie with pagination, the aggregate rating is on the movie detail",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "get movie reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "newest",
+                            "oldest",
+                            "highest",
+                            "lowest"
+                        ],
+                        "type": "string",
+                        "description": "sort order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Review"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "rate a movie 1-5 with an optional text, only users with a paid order for a showtime that has passed may review, once per movie. reviews containing listed words are held for moderation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "review a movie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "review",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "not a verified viewer",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "already reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/reviews/{review_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "delete own review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "no such review of yours",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "a changed body is checked again and may send the review back to moderation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "edit own review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "fields to change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateReviewBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "no such review of yours",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/reviews/{review_id}/report": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "report another user's review, it is hidden and sent back to the moderation queue. a user files at most 5 reports a day",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "report a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "reason",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportReviewBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "own review",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "already reported",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many reports today",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/schedule": {
+            "get": {
+                "description": "Retrieve available movie schedules based on movie ID, date, time, and location.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get movie schedule filter",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by time (HHMM in 24h format, e.g. 2030)",
+                        "name": "time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by location ID",
+                        "name": "location",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by screen format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by audio language",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by subtitle language",
+                        "name": "subtitle",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieScheduleFilterResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No matching schedule found",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieScheduleFilterResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieScheduleFilterResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/schedules": {
+            "get": {
+                "description": "movie schedules (date, time, location, cinema) for a given movie ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "get movie schedules by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schedules retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieSchedulesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieSchedulesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/showtimes": {
+            "get": {
+                "description": "upcoming showtimes of a movie grouped by date, location and cinema, with remaining seats and price",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "get grouped movie showtimes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "IMAX",
+                        "description": "Filter by screen format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "en",
+                        "description": "Filter by audio language",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "id",
+                        "description": "Filter by subtitle language",
+                        "name": "subtitle",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Showtimes retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieShowtimesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid movie id",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieShowtimesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "no upcoming showtimes",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieShowtimesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieShowtimesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "create a new user's order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "create user order handler func",
+                "parameters": [
+                    {
+                        "description": "Order body",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CinemaOrderBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized: invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Buyer is under the age rating or has no birthdate",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/{id}": {
+            "get": {
+                "description": "movies the person acted in, directed and crewed as writer, producer or composer, newest first. the ID is a cast ID, directors link to theirs with director_person_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "get a person's filmography",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.FulfilledResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "$ref": "#/definitions/models.Person"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "person not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules": {
+            "get": {
+                "description": "Retrieve upcoming cinema schedules, no login required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get cinema schedules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by movie ID",
+                        "name": "movie_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by show date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Bogor",
+                        "description": "Filter by city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by cinema ID",
+                        "name": "cinema_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "IMAX",
+                        "description": "Filter by screen format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "en",
+                        "description": "Filter by audio language",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "id",
+                        "description": "Filter by subtitle language",
+                        "name": "subtitle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schedules fetched successfully or no schedules available",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the schedule",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/formats": {
+            "get": {
+                "description": "Retrieve screen formats (2D, 3D, IMAX, 4DX) and their price surcharge",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Get screen formats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get user's profile details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get user profile info based from ID",
+                "responses": {
+                    "200": {
+                        "description": "User profile retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserinfResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserinfResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserinfResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update user's profile details via multipart form",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user profile info by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First name",
+                        "name": "first_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last name",
+                        "name": "last_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Phone number (e.g., 08667728761)",
+                        "name": "phone_number",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "User's point (e.g., 4.8)",
+                        "name": "point_count",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Avatar image file",
+                        "name": "avatar",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Birthdate (YYYY-MM-DD), checked against movie age ratings",
+                        "name": "birthdate",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User profile updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or no user found",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while updating profile",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "notifications such as cancelled showtimes or tickets opening for a watchlisted movie, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get user notifications",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Notification"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/notifications/read": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "mark every unread notification of the user as read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "mark notifications as read",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get user's order watch histories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get user order history info based from ID",
+                "responses": {
+                    "200": {
+                        "description": "User order history retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoryResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "No order history found for user",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoryResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoryResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "allows user's to update their password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "update user's password",
+                "parameters": [
+                    {
+                        "description": "new password JSON body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PasswordBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "password updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.EditPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request - validation failed or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.EditPasswordResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "server error while updating password",
+                        "schema": {
+                            "$ref": "#/definitions/models.EditPasswordResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "movies with an upcoming showtime the user has not booked, scored by the genres, directors and casts of their past orders blended with popularity",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get movie recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Recommendation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/watchlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "movies saved by the user, latest first. tickets_open tells whether an upcoming showtime can be booked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get user watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 12,
+                        "description": "items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "language, defaults to Accept-Language then en",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "result": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.WatchlistMovie"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/watchlist/{movie_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "save a movie, the user is notified when its first showtime is scheduled. saving a movie twice is a no-op",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "add a movie to the watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "remove a movie from the watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "movie ID",
+                        "name": "movie_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FulfilledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not in watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AvailSeatsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Seat"
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.BoxOfficeOrderBody": {
+            "type": "object",
+            "properties": {
+                "override_age_check": {
+                    "description": "sell even when the buyer fails the age check of the movie",
+                    "type": "boolean",
+                    "example": false
+                },
+                "paid_at": {
+                    "type": "boolean"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "example": "PayPal"
+                },
+                "schedule_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Cast": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CastCredit": {
+            "type": "object",
+            "properties": {
+                "character": {
+                    "type": "string",
+                    "example": "Forrest Gump"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Tom Hanks"
+                },
+                "order": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "person_id": {
+                    "type": "integer",
+                    "example": 31
+                }
+            }
+        },
+        "models.CinemaOrderBody": {
+            "type": "object",
+            "properties": {
+                "paid_at": {
+                    "type": "boolean"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "example": "PayPal"
+                },
+                "schedule_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CinemaSchedule": {
+            "type": "object",
+            "properties": {
+                "audio_language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "cinema_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "cinema_img": {
+                    "type": "string"
+                },
+                "cinema_name": {
+                    "type": "string",
+                    "example": "ebv"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "IMAX"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Bogor"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 680
+                },
+                "poster_path": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 65000
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-10-21T19:30:00+08:00"
+                },
+                "subtitle_language": {
+                    "type": "string",
+                    "example": "id"
+                },
+                "time": {
+                    "type": "string",
+                    "example": "13:00"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Makassar"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Pulp Fiction"
+                },
+                "utc_offset": {
+                    "type": "string",
+                    "example": "+08:00"
+                }
+            }
+        },
+        "models.Collection": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionMovie"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "The Avengers Collection"
+                },
+                "overview": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CollectionBody": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "The Avengers Collection"
+                },
+                "overview": {
+                    "type": "string",
+                    "example": "Earth's mightiest heroes"
+                }
+            }
+        },
+        "models.CollectionMovie": {
+            "type": "object",
+            "properties": {
+                "age_rating": {
+                    "type": "string",
+                    "example": "13+"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 24428
+                },
+                "next_showtime": {
+                    "type": "string"
+                },
+                "now_showing": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "poster_path": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2012-04-25"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Avengers"
+                }
+            }
+        },
+        "models.CollectionMoviesBody": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        24428,
+                        99861
+                    ]
+                }
+            }
+        },
+        "models.CollectionSummary": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "movie_count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "The Avengers Collection"
+                }
+            }
+        },
+        "models.CreateMovieResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CreateScheduleBody": {
+            "type": "object",
+            "required": [
+                "location",
+                "movie_id",
+                "schedule_date",
+                "schedule_time"
+            ],
+            "properties": {
+                "audio_language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "2D"
+                },
+                "location": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "movie_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 50000
+                },
+                "schedule_date": {
+                    "type": "string",
+                    "example": "2025-10-21"
+                },
+                "schedule_time": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "subtitle_language": {
+                    "type": "string",
+                    "example": "id"
+                }
+            }
+        },
+        "models.CrewCredit": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "type": "string",
+                    "example": "director"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Robert Zemeckis"
+                },
+                "person_id": {
+                    "type": "integer",
+                    "example": 40
+                }
+            }
+        },
+        "models.DeleteMovieResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string",
+                    "example": "movie w/ ID 1 deleted succesfully"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.EditPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.FulfilledResponse": {
+            "type": "object",
+            "properties": {
+                "result": {},
+                "status": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Genre": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GenreBody": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Biography"
+                }
+            }
+        },
+        "models.GenreTranslation": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "id"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Petualangan"
+                }
+            }
+        },
+        "models.GenreTranslationBody": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Petualangan"
+                }
+            }
+        },
+        "models.HistoryResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/models.UserOrder"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "line": {
+                    "description": "file line for CSV, item number for JSON",
+                    "type": "integer",
+                    "example": 3
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LocationTimezoneBody": {
+            "type": "object",
+            "required": [
+                "timezone"
+            ],
+            "properties": {
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Makassar"
+                }
+            }
+        },
+        "models.Login": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "foo_bar@mail.com"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "logged in as UID 123"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6..."
+                }
+            }
+        },
+        "models.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "server error while logout"
+                },
+                "result": {
+                    "type": "string",
+                    "example": "logout succesfully"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MediaOrderBody": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "models.ModerationReview": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string",
+                    "example": "Still holds up."
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "flagged_words": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 680
+                },
+                "movie_title": {
+                    "type": "string",
+                    "example": "Pulp Fiction"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewReport"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "user_name": {
+                    "type": "string",
+                    "example": "Jane D."
+                }
+            }
+        },
+        "models.Movie": {
+            "type": "object",
+            "properties": {
+                "age_rating": {
+                    "type": "string",
+                    "example": "13+"
+                },
+                "backdrop_path": {
+                    "type": "string"
+                },
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Cast"
+                    }
+                },
+                "collection": {
+                    "description": "the franchise of the movie with its other titles, null when it has none",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MovieCollection"
+                        }
+                    ]
+                },
+                "credits": {
+                    "description": "cast with characters and crew with jobs, cast is the same people",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MovieCredits"
+                        }
+                    ]
+                },
+                "director_name": {
+                    "type": "string",
+                    "example": "Mick Jagger"
+                },
+                "director_person_id": {
+                    "description": "the director's /people page",
+                    "type": "integer",
+                    "example": 31
+                },
+                "genres": {
+                    "description": "Popularity   *float32  ` + "`" + `db:\"popularity\" json:\"popularity\" example:\"17.246\" form:\"popularity\"` + "`" + `",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Genre"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 680
+                },
+                "media": {
+                    "description": "gallery of posters, backdrops, stills and trailers, primary first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovieMedia"
+                    }
+                },
+                "overview": {
+                    "type": "string"
+                },
+                "poster_path": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.25
+                },
+                "rating_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "1994-09-10"
+                },
+                "runtime": {
+                    "type": "integer",
+                    "example": 154
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Pulp Fiction"
+                },
+                "watchlisted": {
+                    "description": "set only for signed in users",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MovieBulkResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/models.MovieBulkResult"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MovieBulkResult": {
+            "type": "object",
+            "properties": {
+                "affected": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "not_found": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.MovieCollection": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "The Avengers Collection"
+                },
+                "siblings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionMovie"
+                    }
+                }
+            }
+        },
+        "models.MovieCredits": {
+            "type": "object",
+            "properties": {
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CastCredit"
+                    }
+                },
+                "crew": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CrewCredit"
+                    }
+                }
+            }
+        },
+        "models.MovieFilter": {
+            "type": "object",
+            "properties": {
+                "age_rating": {
+                    "type": "string",
+                    "example": "13+"
+                },
+                "casts": {
+                    "type": "string"
+                },
+                "director": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Genre"
+                    }
+                },
+                "highlight": {
+                    "$ref": "#/definitions/models.SearchHighlight"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "overview": {
+                    "type": "string"
+                },
+                "popularity": {
+                    "type": "number"
+                },
+                "poster_path": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.25
+                },
+                "rating_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "relevance": {
+                    "description": "set only when searching with q",
+                    "type": "number",
+                    "example": 0.87
+                },
+                "runtime": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "watchlisted": {
+                    "description": "set only for signed in users",
+                    "type": "boolean"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AvailSeatsResponse": {
+        },
+        "models.MovieIDsBody": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "models.MovieImportResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string"
                 },
                 "result": {
+                    "$ref": "#/definitions/models.MovieImportResult"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MovieImportResult": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Seat"
+                        "$ref": "#/definitions/models.ImportRowError"
+                    }
+                },
+                "imported": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
                 },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MovieMedia": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_primary": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "kind": {
+                    "description": "poster, backdrop, still or trailer",
+                    "type": "string",
+                    "example": "trailer"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "provider": {
+                    "description": "trailers only: youtube, vimeo or file",
+                    "type": "string",
+                    "example": "youtube"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=s7EdQ4FqbhY"
+                }
+            }
+        },
+        "models.MovieResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/models.Movie"
+                },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "models.Cast": {
+        "models.MovieSchedule": {
             "type": "object",
             "properties": {
-                "id": {
+                "movie_id": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "schedule": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Schedule"
+                    }
                 }
             }
         },
-        "models.CinemaOrder": {
+        "models.MovieScheduleFilter": {
             "type": "object",
             "properties": {
-                "is_paid": {
-                    "type": "boolean",
-                    "example": false
+                "audio_language": {
+                    "type": "string",
+                    "example": "en"
                 },
-                "payment_method": {
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "cinema_img": {
+                    "type": "string"
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "format": {
                     "type": "string",
-                    "example": "PayPal"
+                    "example": "IMAX"
+                },
+                "price": {
+                    "type": "integer"
                 },
                 "schedule_id": {
-                    "type": "integer",
-                    "example": 12
+                    "type": "integer"
                 },
-                "seats": {
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-10-21T19:30:00+08:00"
+                },
+                "subtitle_language": {
+                    "type": "string",
+                    "example": "id"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Makassar"
+                },
+                "utc_offset": {
+                    "type": "string",
+                    "example": "+08:00"
+                }
+            }
+        },
+        "models.MovieScheduleFilterResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.MovieScheduleFilter"
                     }
                 },
-                "total": {
-                    "type": "integer",
-                    "example": 30
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MovieSchedulesResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
                 },
-                "user_id": {
+                "result": {
+                    "$ref": "#/definitions/models.MovieSchedule"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MovieShowtimes": {
+            "type": "object",
+            "properties": {
+                "dates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShowtimeDate"
+                    }
+                },
+                "movie_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.CinemaSchedule": {
+        "models.MovieShowtimesResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/models.MovieShowtimes"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MovieTranslation": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "id"
+                },
+                "overview": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Kisah Cinta Forrest"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MovieTranslationBody": {
+            "type": "object",
+            "properties": {
+                "overview": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Kisah Cinta Forrest"
+                }
+            }
+        },
+        "models.MoviesResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovieFilter"
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "schedule_cancelled"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "movie_id": {
+                    "type": "integer",
+                    "example": 680
+                },
+                "order_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Showtime cancelled"
+                }
+            }
+        },
+        "models.NotificationsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.OrderHistory": {
             "type": "object",
             "properties": {
+                "cancelled_at": {
+                    "description": "set when the showtime was cancelled",
+                    "type": "string"
+                },
                 "cinema_name": {
                     "type": "string",
                     "example": "ebv"
@@ -1093,23 +6426,43 @@ const docTemplate = `{
                 "date": {
                     "type": "string"
                 },
-                "location": {
+                "id": {
+                    "type": "integer"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "starts_at": {
                     "type": "string",
-                    "example": "Bogor"
-                },
-                "schedule_id": {
-                    "type": "integer"
+                    "example": "2025-10-21T19:30:00+08:00"
                 },
                 "time": {
                     "type": "string"
                 },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Makassar"
+                },
                 "title": {
                     "type": "string",
                     "example": "Pulp Fiction"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "utc_offset": {
+                    "type": "string",
+                    "example": "+08:00"
                 }
             }
         },
-        "models.CreateMovieResponse": {
+        "models.OrderResponse": {
             "type": "object",
             "properties": {
                 "error": {
@@ -1123,126 +6476,186 @@ const docTemplate = `{
                 }
             }
         },
-        "models.DeleteMovieResponse": {
+        "models.PaginatedResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer",
+                    "example": 20
                 },
-                "result": {
-                    "type": "string",
-                    "example": "movie w/ ID 1 deleted succesfully"
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "result": {},
+                "status": {
+                    "type": "integer"
                 },
                 "success": {
                     "type": "boolean"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 57
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "models.EditPasswordResponse": {
+        "models.PasswordBody": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
-                "error": {
-                    "type": "string"
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8
                 },
-                "result": {
+                "old_password": {
                     "type": "string"
-                },
-                "success": {
-                    "type": "boolean"
                 }
             }
         },
-        "models.Genre": {
+        "models.PeopleMergeBody": {
             "type": "object",
+            "required": [
+                "ids"
+            ],
             "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        40
+                    ]
                 }
             }
         },
-        "models.HistoryResponse": {
+        "models.Person": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "acted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PersonCredit"
+                    }
                 },
-                "result": {
-                    "$ref": "#/definitions/models.UserOrder"
+                "crew": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PersonCredit"
+                    }
                 },
-                "success": {
-                    "type": "boolean"
+                "directed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PersonCredit"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 31
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Tom Hanks"
                 }
             }
         },
-        "models.Login": {
+        "models.PersonBody": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "name"
             ],
             "properties": {
-                "email": {
+                "name": {
                     "type": "string",
-                    "example": "foo_bar@mail.com"
-                },
-                "password": {
-                    "type": "string"
+                    "maxLength": 255,
+                    "example": "Tom Hanks"
                 }
             }
         },
-        "models.LoginResponse": {
+        "models.PersonCredit": {
             "type": "object",
             "properties": {
-                "message": {
+                "age_rating": {
                     "type": "string",
-                    "example": "logged in as UID 123"
+                    "example": "13+"
                 },
-                "success": {
-                    "type": "boolean"
+                "character": {
+                    "description": "acted only",
+                    "type": "string",
+                    "example": "Forrest Gump"
                 },
-                "token": {
+                "id": {
+                    "type": "integer",
+                    "example": 13
+                },
+                "job": {
+                    "description": "crew only",
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6..."
+                    "example": "producer"
+                },
+                "poster_path": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.25
+                },
+                "rating_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "1994-06-23"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Forrest Gump"
                 }
             }
         },
-        "models.LogoutResponse": {
+        "models.PersonSummary": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "server error while logout"
+                "id": {
+                    "type": "integer",
+                    "example": 31
                 },
-                "result": {
-                    "type": "string",
-                    "example": "logout succesfully"
+                "movie_count": {
+                    "description": "trashed movies included",
+                    "type": "integer",
+                    "example": 4
                 },
-                "success": {
-                    "type": "boolean"
+                "name": {
+                    "type": "string",
+                    "example": "Tom Hanks"
                 }
             }
         },
-        "models.Movie": {
+        "models.Recommendation": {
             "type": "object",
             "properties": {
-                "backdrop_path": {
-                    "type": "string"
-                },
-                "cast": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Cast"
-                    }
+                "affinity": {
+                    "description": "share of the user's booked genres, directors and casts the movie matches, 0-1",
+                    "type": "number",
+                    "example": 0.62
                 },
-                "director_name": {
+                "age_rating": {
                     "type": "string",
-                    "example": "Mick Jagger"
+                    "example": "13+"
                 },
                 "genres": {
-                    "description": "Popularity   *float32  ` + "`" + `db:\"popularity\" json:\"popularity\" example:\"17.246\" form:\"popularity\"` + "`" + `",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Genre"
@@ -1252,19 +6665,24 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 680
                 },
-                "overview": {
-                    "type": "string"
-                },
                 "poster_path": {
                     "type": "string"
                 },
+                "rating": {
+                    "type": "number",
+                    "example": 4.25
+                },
+                "rating_count": {
+                    "type": "integer",
+                    "example": 12
+                },
                 "release_date": {
                     "type": "string",
                     "example": "1994-09-10"
                 },
-                "runtime": {
-                    "type": "integer",
-                    "example": 154
+                "score": {
+                    "type": "number",
+                    "example": 0.71
                 },
                 "title": {
                     "type": "string",
@@ -1272,228 +6690,399 @@ const docTemplate = `{
                 }
             }
         },
-        "models.MovieResponse": {
+        "models.Register": {
             "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
             "properties": {
-                "error": {
-                    "type": "string"
+                "email": {
+                    "description": "ID       uint16 ` + "`" + `db:\"id\" json:\"id\"` + "`" + `",
+                    "type": "string",
+                    "example": "foo_bar@mail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "models.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "server unable to bind request"
                 },
                 "result": {
-                    "$ref": "#/definitions/models.Movie"
+                    "type": "string",
+                    "example": "register succesfully w/ ID: 37"
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "models.MovieSchedule": {
+        "models.ReportReviewBody": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "spoilers"
+                }
+            }
+        },
+        "models.Review": {
             "type": "object",
             "properties": {
+                "avatar": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string",
+                    "example": "Still holds up."
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "movie_id": {
-                    "type": "integer"
+                    "type": "integer",
+                    "example": 680
                 },
-                "schedule": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Schedule"
-                    }
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "user_name": {
+                    "type": "string",
+                    "example": "Jane D."
                 }
             }
         },
-        "models.MovieScheduleFilter": {
+        "models.ReviewBody": {
+            "type": "object",
+            "required": [
+                "rating"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Still holds up."
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 5
+                }
+            }
+        },
+        "models.ReviewReport": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "spoilers"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.Schedule": {
+            "type": "object",
+            "properties": {
+                "audio_language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "cinema": {
+                    "type": "string"
+                },
                 "cinema_img": {
                     "type": "string"
                 },
-                "cinema_name": {
+                "date": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "IMAX"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-10-21T19:30:00+08:00"
+                },
+                "subtitle_language": {
+                    "type": "string",
+                    "example": "id"
+                },
+                "time": {
                     "type": "string"
                 },
-                "schedule_id": {
-                    "type": "integer"
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Makassar"
+                },
+                "utc_offset": {
+                    "type": "string",
+                    "example": "+08:00"
                 }
             }
         },
-        "models.MovieScheduleFilterResponse": {
+        "models.ScheduleResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "message": {
                     "type": "string"
                 },
-                "result": {
+                "schedules": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.MovieScheduleFilter"
+                        "$ref": "#/definitions/models.CinemaSchedule"
                     }
                 },
                 "success": {
-                    "type": "boolean"
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "models.MovieSchedulesResponse": {
+        "models.SearchHighlight": {
             "type": "object",
             "properties": {
-                "error": {
+                "matched_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "title",
+                        "cast"
+                    ]
+                },
+                "overview": {
                     "type": "string"
                 },
-                "result": {
-                    "$ref": "#/definitions/models.MovieSchedule"
+                "title": {
+                    "type": "string",
+                    "example": "\u003cmark\u003ePulp\u003c/mark\u003e Fiction"
+                }
+            }
+        },
+        "models.Seat": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 32
                 },
-                "success": {
-                    "type": "boolean"
+                "pos": {
+                    "type": "string",
+                    "example": "C4"
                 }
             }
         },
-        "models.OrderHistory": {
+        "models.Showtime": {
             "type": "object",
             "properties": {
-                "cinema_name": {
+                "audio_language": {
                     "type": "string",
-                    "example": "ebv"
+                    "example": "en"
                 },
-                "date": {
-                    "type": "string"
+                "format": {
+                    "type": "string",
+                    "example": "IMAX"
                 },
-                "id": {
+                "price": {
+                    "type": "integer",
+                    "example": 50000
+                },
+                "remaining_seats": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "schedule_id": {
                     "type": "integer"
                 },
-                "is_paid": {
-                    "type": "boolean"
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-10-21T19:30:00+08:00"
                 },
-                "seats": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "subtitle_language": {
+                    "type": "string",
+                    "example": "id"
                 },
                 "time": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "13:00"
                 },
-                "title": {
+                "timezone": {
                     "type": "string",
-                    "example": "Pulp Fiction"
+                    "example": "Asia/Makassar"
                 },
-                "user_id": {
-                    "type": "integer"
+                "utc_offset": {
+                    "type": "string",
+                    "example": "+08:00"
                 }
             }
         },
-        "models.OrderResponse": {
+        "models.ShowtimeCinema": {
             "type": "object",
             "properties": {
-                "error": {
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "cinema_img": {
                     "type": "string"
                 },
-                "result": {
+                "cinema_name": {
+                    "type": "string",
+                    "example": "ebv"
+                },
+                "showtimes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Showtime"
+                    }
+                }
+            }
+        },
+        "models.ShowtimeDate": {
+            "type": "object",
+            "properties": {
+                "date": {
                     "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShowtimeLocation"
+                    }
                 }
             }
         },
-        "models.PasswordBody": {
+        "models.ShowtimeLocation": {
             "type": "object",
-            "required": [
-                "new_password",
-                "old_password"
-            ],
             "properties": {
-                "new_password": {
+                "cinemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShowtimeCinema"
+                    }
+                },
+                "location": {
                     "type": "string",
-                    "minLength": 8
+                    "example": "Bogor"
                 },
-                "old_password": {
-                    "type": "string"
+                "location_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Register": {
+        "models.Suggestion": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "description": "ID       uint16 ` + "`" + `db:\"id\" json:\"id\"` + "`" + `",
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "label": {
                     "type": "string",
-                    "example": "foo_bar@mail.com"
+                    "example": "Pulp Fiction"
                 },
-                "password": {
+                "score": {
+                    "type": "number",
+                    "example": 87.5
+                },
+                "type": {
+                    "description": "movie, director or cast",
                     "type": "string",
-                    "minLength": 8
+                    "example": "movie"
                 }
             }
         },
-        "models.RegisterResponse": {
+        "models.SuggestionsResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "server unable to bind request"
+                "error": {
+                    "type": "string"
                 },
                 "result": {
-                    "type": "string",
-                    "example": "register succesfully w/ ID: 37"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Suggestion"
+                    }
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "models.Schedule": {
+        "models.TrashedMovie": {
             "type": "object",
             "properties": {
-                "cinema": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "cinema_img": {
-                    "type": "string"
+                "has_orders": {
+                    "description": "orders block a purge",
+                    "type": "boolean"
                 },
-                "date": {
-                    "type": "string"
+                "id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "location": {
+                "poster_path": {
                     "type": "string"
                 },
-                "schedule_id": {
-                    "type": "integer"
-                },
-                "time": {
+                "release_date": {
                     "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Pulp Fiction"
                 }
             }
         },
-        "models.ScheduleResponse": {
+        "models.UpdateCinemaBody": {
             "type": "object",
             "properties": {
-                "message": {
+                "address": {
                     "type": "string"
                 },
-                "schedules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.CinemaSchedule"
-                    }
-                },
-                "success": {
-                    "type": "boolean",
-                    "example": true
-                }
-            }
-        },
-        "models.Seat": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 32
+                "latitude": {
+                    "type": "number"
                 },
-                "pos": {
-                    "type": "string",
-                    "example": "C4"
+                "longitude": {
+                    "type": "number"
                 }
             }
         },
@@ -1526,12 +7115,53 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateReviewBody": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 4
+                }
+            }
+        },
+        "models.UpdateScheduleBody": {
+            "type": "object",
+            "properties": {
+                "audio_language": {
+                    "type": "string",
+                    "example": "ja"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "3D"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 50000
+                },
+                "subtitle_language": {
+                    "type": "string",
+                    "example": "id"
+                }
+            }
+        },
         "models.UserInf": {
             "type": "object",
             "properties": {
                 "avatar": {
                     "type": "string"
                 },
+                "birthdate": {
+                    "description": "used for the LSF age check on orders",
+                    "type": "string",
+                    "example": "2001-08-17T00:00:00Z"
+                },
                 "first_name": {
                     "type": "string"
                 },
@@ -1581,6 +7211,67 @@ const docTemplate = `{
                     "type": "boolean"
                 }
             }
+        },
+        "models.WatchlistMovie": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "age_rating": {
+                    "type": "string",
+                    "example": "13+"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Genre"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 680
+                },
+                "poster_path": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "1994-09-10"
+                },
+                "tickets_open": {
+                    "description": "an upcoming showtime can be booked",
+                    "type": "boolean",
+                    "example": false
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Pulp Fiction"
+                }
+            }
+        },
+        "pkg.MetadataCandidate": {
+            "type": "object",
+            "properties": {
+                "external_id": {
+                    "type": "string",
+                    "example": "680"
+                },
+                "overview": {
+                    "type": "string"
+                },
+                "poster_url": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "1994-09-10"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Pulp Fiction"
+                }
+            }
         }
     },
     "securityDefinitions": {
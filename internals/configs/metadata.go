@@ -0,0 +1,22 @@
+package configs
+
+import (
+	"os"
+
+	"github.com/metgag/koda-weekly10/pkg"
+)
+
+// InitMetadataProvider points the TMDB provider at TMDB_BASE_URL so a
+// fixture server can stand in for the real API
+func InitMetadataProvider() pkg.MetadataProvider {
+	baseURL := os.Getenv("TMDB_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.themoviedb.org/3"
+	}
+	imageURL := os.Getenv("TMDB_IMAGE_URL")
+	if imageURL == "" {
+		imageURL = "https://image.tmdb.org/t/p/original"
+	}
+
+	return pkg.NewTMDBProvider(baseURL, imageURL, os.Getenv("TMDB_API_KEY"))
+}
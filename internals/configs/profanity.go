@@ -0,0 +1,31 @@
+package configs
+
+import (
+	"log"
+	"os"
+
+	"github.com/metgag/koda-weekly10/pkg"
+)
+
+// InitProfanityFilter loads the word list at PROFANITY_WORDLIST, falling back
+// to the built-in English and Indonesian lists
+func InitProfanityFilter() *pkg.ProfanityFilter {
+	path := os.Getenv("PROFANITY_WORDLIST")
+	if path == "" {
+		return pkg.NewDefaultProfanityFilter()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("unable to open profanity word list, using defaults: %s\n", err)
+		return pkg.NewDefaultProfanityFilter()
+	}
+	defer file.Close()
+
+	words, err := pkg.ReadProfanityList(file)
+	if err != nil {
+		log.Printf("unable to read profanity word list, using defaults: %s\n", err)
+		return pkg.NewDefaultProfanityFilter()
+	}
+	return pkg.NewProfanityFilter(words)
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/metgag/koda-weekly10/internals/models"
 	"github.com/metgag/koda-weekly10/internals/repositories"
 	"github.com/metgag/koda-weekly10/internals/utils"
@@ -25,33 +29,412 @@ func newScheduleResponse(res []models.CinemaSchedule, success bool, error string
 // HandleCinemaSchedule godoc
 //
 //	@Summary		Get cinema schedules
-//	@Description	Retrieve all available cinema schedules from the server
+//	@Description	Retrieve upcoming cinema schedules, no login required
 //	@Tags			cinemas
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	models.ScheduleResponse	"Schedules fetched successfully or no schedules available"
-//	@Failure		500	{object}	models.ScheduleResponse	"Internal server error while fetching the schedule"
-//	@Security		BearerAuth
-//	@Router			/cinemas/schedules [get]
+//	@Param			movie_id	query		int							false	"Filter by movie ID"
+//	@Param			date		query		string						false	"Filter by show date (YYYY-MM-DD)"
+//	@Param			city		query		string						false	"Filter by city"	example(Bogor)
+//	@Param			cinema_id	query		int							false	"Filter by cinema ID"
+//	@Param			format		query		string						false	"Filter by screen format"						example(IMAX)
+//	@Param			audio		query		string						false	"Filter by audio language"						example(en)
+//	@Param			subtitle	query		string						false	"Filter by subtitle language"					example(id)
+//	@Param			page		query		int							false	"page number"									example(1)
+//	@Param			limit		query		int							false	"items per page"								example(20)
+//	@Param			lang		query		string						false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200			{object}	models.PaginatedResponse	"Schedules fetched successfully or no schedules available"
+//	@Failure		400			{object}	models.ScheduleResponse		"Invalid filter"
+//	@Failure		500			{object}	models.ScheduleResponse		"Internal server error while fetching the schedule"
+//	@Router			/schedules [get]
 func (c *CinemaHandler) HandlerSchedule(ctx *gin.Context) {
-	schedule, err := c.cr.GetSchedule(ctx.Request.Context())
+	var filter models.ScheduleFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utils.PrintError("INVALID SCHEDULE FILTER", 8, err)
+		ctx.JSON(http.StatusBadRequest, newScheduleResponse(
+			nil, false, "invalid schedule filter",
+		))
+		return
+	}
+	if filter.Date != "" {
+		if _, err := time.Parse("2006-01-02", filter.Date); err != nil {
+			utils.PrintError("INVALID SCHEDULE DATE", 8, err)
+			ctx.JSON(http.StatusBadRequest, newScheduleResponse(
+				nil, false, "date must be in YYYY-MM-DD format",
+			))
+			return
+		}
+	}
+	page, limit, offset := utils.GetPagination(ctx, 20, 100)
+
+	schedule, total, err := c.cr.GetSchedules(ctx.Request.Context(), filter, limit, offset, utils.GetLanguage(ctx))
 	if err != nil {
 		utils.PrintError("CINEMA SCHEDULE SERVER ERROR", 8, err)
 		ctx.JSON(http.StatusInternalServerError, newScheduleResponse(
 			nil, false, "server unable to get cinema schedule",
 		))
+		return
 	}
 
-	if len(schedule) == 0 {
-		utils.PrintError("NO CINEMA SCHEDULES", 12, nil)
-		ctx.JSON(http.StatusOK, newScheduleResponse(
-			[]models.CinemaSchedule{}, true, "no cinema schedules available",
-		))
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK,
+		schedule,
+		models.NewPageInfo(page, limit, total),
+	))
+}
+
+// HandleScreenFormats godoc
+//
+//	@Summary		Get screen formats
+//	@Description	Retrieve screen formats (2D, 3D, IMAX, 4DX) and their price surcharge
+//	@Tags			cinemas
+//	@Produce		json
+//	@Success		200	{object}	models.FulfilledResponse
+//	@Failure		500	{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/schedules/formats [get]
+func (c *CinemaHandler) HandleScreenFormats(ctx *gin.Context) {
+	formats, err := c.cr.GetScreenFormats(ctx.Request.Context())
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"SERVER UNABLE GET SCREEN FORMATS",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		formats,
+	))
+}
+
+// HandleCinemas godoc
+//
+//	@Summary		Get cinemas
+//	@Description	Retrieve all cinemas along with the cities they screen in
+//	@Tags			cinemas
+//	@Produce		json
+//	@Success		200	{object}	models.FulfilledResponse
+//	@Failure		500	{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/cinemas [get]
+func (c *CinemaHandler) HandleCinemas(ctx *gin.Context) {
+	cinemas, err := c.cr.GetCinemas(ctx.Request.Context())
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"SERVER UNABLE GET CINEMAS",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		cinemas,
+	))
+}
+
+// HandleCinemaDetail godoc
+//
+//	@Summary		Get cinema detail
+//	@Description	Retrieve a cinema and the cities it screens in
+//	@Tags			cinemas
+//	@Produce		json
+//	@Param			cinema_id	path		int	true	"Cinema ID"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse	"Invalid cinema ID"
+//	@Failure		404			{object}	models.ErrorResponse	"Cinema not found"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/cinemas/detail/{cinema_id} [get]
+func (c *CinemaHandler) HandleCinemaDetail(ctx *gin.Context) {
+	cinemaId, err := strconv.Atoi(ctx.Param("cinema_id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID CINEMA ID",
+			"Invalid cinema ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	cinema, err := c.cr.GetCinemaDetail(ctx.Request.Context(), cinemaId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.LogCtxError(
+				ctx,
+				"CINEMA NOT FOUND",
+				fmt.Sprintf("No cinema w/ ID %d", cinemaId),
+				err,
+				http.StatusNotFound,
+			)
+			return
+		}
+		utils.LogCtxError(
+			ctx,
+			"SERVER UNABLE GET CINEMA DETAIL",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		cinema,
+	))
+}
+
+// HandleNearbyCinemas godoc
+//
+//	@Summary		Get nearby cinemas
+//	@Description	Retrieve cinemas within a radius ordered by distance, optionally with today's remaining showtimes of a movie
+//	@Tags			cinemas
+//	@Produce		json
+//	@Param			lat			query		number	true	"Latitude"	example(-6.5971)
+//	@Param			lng			query		number	true	"Longitude"	example(106.8060)
+//	@Param			radius		query		number	false	"Radius in km, default 10"	example(5)
+//	@Param			movie_id	query		int		false	"Include today's remaining showtimes of this movie"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse	"Invalid coordinates, radius or movie_id"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/cinemas/nearby [get]
+func (c *CinemaHandler) HandleNearbyCinemas(ctx *gin.Context) {
+	lat, errLat := strconv.ParseFloat(ctx.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(ctx.Query("lng"), 64)
+	if err := errors.Join(errLat, errLng); err != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		if err == nil {
+			err = errors.New("coordinates out of range")
+		}
+		utils.LogCtxError(
+			ctx,
+			"INVALID NEARBY COORDINATES",
+			"lat and lng must be valid coordinates",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	radius := 10.0
+	if r := ctx.Query("radius"); r != "" {
+		parsed, err := strconv.ParseFloat(r, 64)
+		if err != nil || parsed <= 0 || parsed > 100 {
+			if err == nil {
+				err = errors.New("radius out of range")
+			}
+			utils.LogCtxError(
+				ctx,
+				"INVALID NEARBY RADIUS",
+				"radius must be between 0 and 100 km",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+		radius = parsed
+	}
+	movieId := 0
+	if id := ctx.Query("movie_id"); id != "" {
+		parsed, err := strconv.Atoi(id)
+		if err != nil || parsed <= 0 {
+			if err == nil {
+				err = errors.New("movie_id out of range")
+			}
+			utils.LogCtxError(
+				ctx,
+				"INVALID NEARBY MOVIE ID",
+				"movie_id must be a positive number",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+		movieId = parsed
+	}
+
+	cinemas, err := c.cr.GetNearbyCinemas(ctx.Request.Context(), lat, lng, radius, movieId)
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"SERVER UNABLE GET NEARBY CINEMAS",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, newScheduleResponse(
-		schedule, true, "",
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		cinemas,
+	))
+}
+
+// HandleUpdateCinema godoc
+//
+//	@Summary		Update cinema address and coordinates
+//	@Description	latitude and longitude are set together
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			cinema_id	path		int						true	"Cinema ID"
+//	@Param			request		body		models.UpdateCinemaBody	true	"address, latitude, longitude"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse	"Invalid body"
+//	@Failure		404			{object}	models.ErrorResponse	"Cinema not found"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/admin/cinemas/{cinema_id} [patch]
+func (c *CinemaHandler) HandleUpdateCinema(ctx *gin.Context) {
+	cinemaId, err := strconv.Atoi(ctx.Param("cinema_id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID CINEMA ID",
+			"Invalid cinema ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	var body models.UpdateCinemaBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE BIND CINEMA BODY",
+			"Invalid cinema body",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	ctag, err := c.cr.UpdateCinema(ctx.Request.Context(), body, cinemaId)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNothingToUpdate) {
+			utils.LogCtxError(
+				ctx,
+				"EMPTY CINEMA BODY",
+				"Nothing to update",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+		utils.LogCtxError(
+			ctx,
+			"UNABLE UPDATE CINEMA",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	if ctag.RowsAffected() == 0 {
+		utils.LogCtxError(
+			ctx,
+			"CINEMA NOT FOUND",
+			fmt.Sprintf("No cinema w/ ID %d", cinemaId),
+			errors.New("cinema not found"),
+			http.StatusNotFound,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("cinema w/ ID %d updated succesfully", cinemaId),
+	))
+}
+
+// HandleUpdateLocationTimezone godoc
+//
+//	@Summary		Set location timezone
+//	@Description	Set the IANA timezone used to read the showtimes of a location
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			location_id	path		int							true	"Location ID"
+//	@Param			request		body		models.LocationTimezoneBody	true	"IANA timezone"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse	"Invalid timezone"
+//	@Failure		404			{object}	models.ErrorResponse	"Location not found"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/admin/locations/{location_id}/timezone [patch]
+func (c *CinemaHandler) HandleUpdateLocationTimezone(ctx *gin.Context) {
+	locationId, err := strconv.Atoi(ctx.Param("location_id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID LOCATION ID",
+			"Invalid location ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	var body models.LocationTimezoneBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE BIND TIMEZONE BODY",
+			"Invalid timezone body",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+	// Go reads "Local" as the server's zone but postgres does not know it
+	if _, err := time.LoadLocation(body.Timezone); err != nil || body.Timezone == "Local" {
+		if err == nil {
+			err = errors.New("timezone Local is not an IANA name")
+		}
+		utils.LogCtxError(
+			ctx,
+			"INVALID TIMEZONE",
+			"timezone must be an IANA name, e.g. Asia/Makassar",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	ctag, err := c.cr.UpdateLocationTimezone(ctx.Request.Context(), locationId, body.Timezone)
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE UPDATE LOCATION TIMEZONE",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	if ctag.RowsAffected() == 0 {
+		utils.LogCtxError(
+			ctx,
+			"LOCATION NOT FOUND",
+			fmt.Sprintf("No location w/ ID %d", locationId),
+			errors.New("location not found"),
+			http.StatusNotFound,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("location w/ ID %d now uses %s", locationId, body.Timezone),
 	))
 }
 
@@ -69,8 +452,8 @@ func newAvailSeatsRepsonse(res []models.Seat, success bool, err string) models.A
 //	@Param			schedule_id	path		int							true	"The ID of the cinema schedule"
 //	@Success		200			{object}	models.AvailSeatsResponse	"Available seats retrieved successfully or no seats available"
 //	@Failure		400			{object}	models.AvailSeatsResponse	"Invalid schedule ID format"
+//	@Failure		404			{object}	models.AvailSeatsResponse	"Schedule cancelled or not found"
 //	@Failure		500			{object}	models.AvailSeatsResponse	"Internal server error while fetching available seats"
-//	@Security		BearerAuth
 //	@Router			/cinemas/{schedule_id}/seats [get]
 func (c *CinemaHandler) HandlerSeats(ctx *gin.Context) {
 	scheduleIdStr := ctx.Param("schedule_id")
@@ -85,6 +468,13 @@ func (c *CinemaHandler) HandlerSeats(ctx *gin.Context) {
 
 	seats, err := c.cr.GetAvailSeats(ctx, scheduleId)
 	if err != nil {
+		if errors.Is(err, repositories.ErrScheduleNotFound) {
+			utils.PrintError("CINEMA AVAIL SEATS SCHEDULE NOT FOUND", 8, err)
+			ctx.JSON(http.StatusNotFound, newAvailSeatsRepsonse(
+				nil, false, "schedule not found",
+			))
+			return
+		}
 		utils.PrintError("CINEMA AVAIL SEATS SERVER ERROR", 8, err)
 		ctx.JSON(http.StatusInternalServerError, newAvailSeatsRepsonse(
 			nil, false, "server unable to get available seats",
@@ -121,6 +511,13 @@ func (c *CinemaHandler) HandlerCinemaNameAndTime(ctx *gin.Context) {
 
 	result, err := c.cr.GetCinemaNameAndTime(ctx.Request.Context(), scheduleId)
 	if err != nil {
+		if errors.Is(err, repositories.ErrScheduleNotFound) {
+			utils.PrintError("CINEMA AND TIME SCHEDULE NOT FOUND", 16, err)
+			ctx.JSON(http.StatusNotFound, newCinemaAndTimeResponse(
+				models.CinemaAndTime{}, false, "schedule not found",
+			))
+			return
+		}
 		utils.PrintError("ERROR GET CINEMA AND TIME", 16, err)
 		ctx.JSON(http.StatusInternalServerError, newCinemaAndTimeResponse(
 			models.CinemaAndTime{}, false, "SERVER ERROR GET CINEMA AND TIME",
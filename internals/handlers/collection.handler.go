@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+// collectionError writes the response of a failed collection change
+func collectionError(ctx *gin.Context, head string, err error) {
+	msg, status := "Internal server error", http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrCollectionNotFound),
+		errors.Is(err, repositories.ErrMovieNotFound):
+		msg, status = err.Error(), http.StatusNotFound
+	case errors.Is(err, repositories.ErrDuplicateName),
+		errors.Is(err, repositories.ErrMovieInCollection):
+		msg, status = err.Error(), http.StatusConflict
+	case errors.Is(err, repositories.ErrDuplicateCollection):
+		msg, status = err.Error(), http.StatusBadRequest
+	}
+	utils.LogCtxError(
+		ctx,
+		head,
+		msg,
+		err,
+		status,
+	)
+}
+
+func bindCollectionBody(ctx *gin.Context) (models.CollectionBody, bool) {
+	var body models.CollectionBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID COLLECTION BODY",
+			"name is required",
+			err,
+			http.StatusBadRequest,
+		)
+		return models.CollectionBody{}, false
+	}
+	body.Name = strings.TrimSpace(body.Name)
+	return body, true
+}
+
+// HandleGetCollections godoc
+//
+//	@Summary		list collections (admin)
+//	@Tags			admin
+//	@Produce		json
+//	@Param			page	query		int	false	"page number"		example(1)
+//	@Param			limit	query		int	false	"items per page"	example(20)
+//	@Success		200		{object}	models.PaginatedResponse{result=[]models.CollectionSummary}
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/collections [get]
+func (m *MovieHandler) HandleGetCollections(ctx *gin.Context) {
+	page, limit, offset := utils.GetPagination(ctx, 20, 100)
+
+	collections, total, err := m.mr.GetCollections(ctx.Request.Context(), limit, offset)
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE GET COLLECTIONS",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK, collections, models.NewPageInfo(page, limit, total),
+	))
+}
+
+// HandleCreateCollection godoc
+//
+//	@Summary		add a collection (admin)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.CollectionBody	true	"name and overview"
+//	@Success		201		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse	"name is already taken"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/collections [post]
+func (m *MovieHandler) HandleCreateCollection(ctx *gin.Context) {
+	body, ok := bindCollectionBody(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := m.mr.CreateCollection(ctx.Request.Context(), body)
+	if err != nil {
+		collectionError(ctx, "UNABLE CREATE COLLECTION", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.NewFullfilledResponse(
+		http.StatusCreated,
+		fmt.Sprintf("collection w/ ID %d created", id),
+	))
+}
+
+// HandleUpdateCollection godoc
+//
+//	@Summary		rename a collection (admin)
+//	@Description	the overview is kept when not given
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"collection ID"
+//	@Param			body	body		models.CollectionBody	true	"name and overview"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"collection not found"
+//	@Failure		409		{object}	models.ErrorResponse	"name is already taken"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/collections/{id} [patch]
+func (m *MovieHandler) HandleUpdateCollection(ctx *gin.Context) {
+	id, ok := personID(ctx, "collection")
+	if !ok {
+		return
+	}
+	body, ok := bindCollectionBody(ctx)
+	if !ok {
+		return
+	}
+
+	if err := m.mr.UpdateCollection(ctx.Request.Context(), id, body); err != nil {
+		collectionError(ctx, "UNABLE UPDATE COLLECTION", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("collection w/ ID %d updated", id),
+	))
+}
+
+// HandleDeleteCollection godoc
+//
+//	@Summary		delete a collection (admin)
+//	@Description	the movies of the collection are kept
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"collection ID"
+//	@Success		200	{object}	models.FulfilledResponse
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		404	{object}	models.ErrorResponse	"collection not found"
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/collections/{id} [delete]
+func (m *MovieHandler) HandleDeleteCollection(ctx *gin.Context) {
+	id, ok := personID(ctx, "collection")
+	if !ok {
+		return
+	}
+
+	if err := m.mr.DeleteCollection(ctx.Request.Context(), id); err != nil {
+		collectionError(ctx, "UNABLE DELETE COLLECTION", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("collection w/ ID %d deleted", id),
+	))
+}
+
+// HandleSetCollectionMovies godoc
+//
+//	@Summary		set the movies of a collection (admin)
+//	@Description	ids replace the movies of the collection in series order, an empty list empties it. a movie belongs to one collection at most
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"collection ID"
+//	@Param			body	body		models.CollectionMoviesBody	true	"movie IDs in series order"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"collection or movie not found"
+//	@Failure		409		{object}	models.ErrorResponse	"movie already belongs to another collection"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/collections/{id}/movies [put]
+func (m *MovieHandler) HandleSetCollectionMovies(ctx *gin.Context) {
+	id, ok := personID(ctx, "collection")
+	if !ok {
+		return
+	}
+	var body models.CollectionMoviesBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID COLLECTION MOVIES BODY",
+			"ids must list movie IDs",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	if err := m.mr.SetCollectionMovies(ctx.Request.Context(), id, body.IDs); err != nil {
+		collectionError(ctx, "UNABLE SET COLLECTION MOVIES", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("%d movies set in collection w/ ID %d", len(body.IDs), id),
+	))
+}
+
+// HandleGetCollection godoc
+//
+//	@Summary		get a collection
+//	@Description	the movies of a franchise in series order, now_showing and next_showtime tell which can be booked
+//	@Tags			collections
+//	@Produce		json
+//	@Param			id		path		int		true	"collection ID"
+//	@Param			lang	query		string	false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.FulfilledResponse{result=models.Collection}
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"collection not found"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Router			/collections/{id} [get]
+func (m *MovieHandler) HandleGetCollection(ctx *gin.Context) {
+	id, ok := personID(ctx, "collection")
+	if !ok {
+		return
+	}
+
+	collection, err := m.mr.GetCollection(ctx.Request.Context(), id, utils.GetLanguage(ctx))
+	if err != nil {
+		collectionError(ctx, "UNABLE GET COLLECTION", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		collection,
+	))
+}
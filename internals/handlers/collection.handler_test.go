@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+)
+
+func TestCollectionError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{repositories.ErrCollectionNotFound, http.StatusNotFound},
+		{fmt.Errorf("%w: 12", repositories.ErrMovieNotFound), http.StatusNotFound},
+		{repositories.ErrMovieInCollection, http.StatusConflict},
+		{repositories.ErrDuplicateCollection, http.StatusBadRequest},
+		{errors.New("connection reset"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		collectionError(ctx, "TEST", tt.err)
+		if w.Code != tt.want {
+			t.Errorf("collectionError(%v) status %d, want %d", tt.err, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+)
+
+// HandleCreateGenre godoc
+//
+//	@Summary		add a genre (admin)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.GenreBody	true	"genre name"
+//	@Success		201		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse	"name is already taken"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/genres [post]
+func (m *MovieHandler) HandleCreateGenre(ctx *gin.Context) {
+	var body models.GenreBody
+	if !bindPersonBody(ctx, &body) {
+		return
+	}
+
+	id, err := m.mr.CreateGenre(ctx.Request.Context(), strings.TrimSpace(body.Name))
+	if err != nil {
+		peopleError(ctx, "UNABLE CREATE GENRE", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.NewFullfilledResponse(
+		http.StatusCreated,
+		fmt.Sprintf("genre w/ ID %d created", id),
+	))
+}
+
+// HandleRenameGenre godoc
+//
+//	@Summary		rename a genre (admin)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"genre ID"
+//	@Param			body	body		models.GenreBody	true	"new name"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"genre not found"
+//	@Failure		409		{object}	models.ErrorResponse	"name is already taken"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/genres/{id} [patch]
+func (m *MovieHandler) HandleRenameGenre(ctx *gin.Context) {
+	id, ok := personID(ctx, "genre")
+	if !ok {
+		return
+	}
+	var body models.GenreBody
+	if !bindPersonBody(ctx, &body) {
+		return
+	}
+
+	if err := m.mr.RenameGenre(ctx.Request.Context(), id, strings.TrimSpace(body.Name)); err != nil {
+		peopleError(ctx, "UNABLE RENAME GENRE", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("genre w/ ID %d renamed", id),
+	))
+}
+
+// HandleDeleteGenre godoc
+//
+//	@Summary		delete a genre (admin)
+//	@Description	only genres no movie uses can be deleted
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"genre ID"
+//	@Success		200	{object}	models.FulfilledResponse
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		404	{object}	models.ErrorResponse	"genre not found"
+//	@Failure		409	{object}	models.ErrorResponse	"genre is still used by movies"
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/genres/{id} [delete]
+func (m *MovieHandler) HandleDeleteGenre(ctx *gin.Context) {
+	id, ok := personID(ctx, "genre")
+	if !ok {
+		return
+	}
+
+	if err := m.mr.DeleteGenre(ctx.Request.Context(), id); err != nil {
+		peopleError(ctx, "UNABLE DELETE GENRE", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("genre w/ ID %d deleted", id),
+	))
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+// mediaParams reads the :id movie and optional :media_id params
+func mediaParams(ctx *gin.Context) (movieId, mediaId int, ok bool) {
+	movieId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MOVIE ID",
+			"Invalid movie ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return 0, 0, false
+	}
+	if param := ctx.Param("media_id"); param != "" {
+		mediaId, err = strconv.Atoi(param)
+		if err != nil {
+			utils.LogCtxError(
+				ctx,
+				"INVALID MEDIA ID",
+				"Invalid media ID",
+				err,
+				http.StatusBadRequest,
+			)
+			return 0, 0, false
+		}
+	}
+	return movieId, mediaId, true
+}
+
+// distinctIDs reports whether no id is listed twice
+func distinctIDs(ids []int) bool {
+	return len(slices.Compact(slices.Sorted(slices.Values(ids)))) == len(ids)
+}
+
+// removeMediaFile deletes an upload nothing refers to, a failure only leaves
+// a stray file behind
+func removeMediaFile(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		utils.PrintError("UNABLE REMOVE MEDIA FILE", 16, err)
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// mediaError writes the response of a failed media change
+func mediaError(ctx *gin.Context, head string, err error) {
+	msg, status := "Internal server error", http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrMovieNotFound):
+		msg, status = "Movie not found", http.StatusNotFound
+	case errors.Is(err, repositories.ErrMediaNotFound):
+		msg, status = err.Error(), http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidMedia):
+		msg, status = err.Error(), http.StatusBadRequest
+	}
+	utils.LogCtxError(
+		ctx,
+		head,
+		msg,
+		err,
+		status,
+	)
+}
+
+// HandleAddMovieMedia godoc
+//
+//	@Summary		add a gallery item (admin)
+//	@Description	upload a poster, backdrop or still, or link one by url. trailers take a video url, the provider is guessed from it when not given. a primary poster or backdrop replaces the one shown in listings
+//	@Tags			admin
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			id			path		int		true	"movie ID"
+//	@Param			kind		formData	string	true	"media kind"	Enums(poster, backdrop, still, trailer)
+//	@Param			file		formData	file	false	"image file"
+//	@Param			url			formData	string	false	"image or video url"
+//	@Param			provider	formData	string	false	"trailer provider"	Enums(youtube, vimeo, file)
+//	@Param			language	formData	string	false	"language code"		example(en)
+//	@Param			caption		formData	string	false	"caption"
+//	@Param			primary		formData	bool	false	"make it the primary of its kind"
+//	@Success		201			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse	"movie not found"
+//	@Failure		500			{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/media [post]
+func (m *MovieHandler) HandleAddMovieMedia(ctx *gin.Context) {
+	movieId, _, ok := mediaParams(ctx)
+	if !ok {
+		return
+	}
+	var body models.MediaBody
+	if err := ctx.ShouldBind(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MEDIA BODY",
+			"kind must be poster, backdrop, still or trailer",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	item := models.MovieMedia{
+		Kind:      body.Kind,
+		URL:       body.URL,
+		Provider:  optionalString(body.Provider),
+		Language:  optionalString(body.Language),
+		Caption:   optionalString(body.Caption),
+		IsPrimary: body.Primary,
+	}
+	if body.File != nil {
+		if _, ok := repositories.MediaDirs[body.Kind]; !ok || body.URL != "" {
+			utils.LogCtxError(
+				ctx,
+				"INVALID MEDIA BODY",
+				"send either an image file or a url, trailers take a url",
+				errors.New("unexpected media file"),
+				http.StatusBadRequest,
+			)
+			return
+		}
+		ext := filepath.Ext(body.File.Filename)
+		filename := fmt.Sprintf("%s_%d_%d%s", body.Kind, movieId, time.Now().UnixNano(), ext)
+		// checked before the file is written
+		item.URL = filename
+		if err := repositories.ValidateMovieMedia(&item, true); err != nil {
+			mediaError(ctx, "INVALID MEDIA FILE", err)
+			return
+		}
+		if err := ctx.SaveUploadedFile(body.File, repositories.MediaFilePath(body.Kind, filename)); err != nil {
+			utils.LogCtxError(
+				ctx,
+				"UNABLE SAVE MEDIA FILE",
+				"unable to upload media file",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+	}
+
+	mediaId, err := m.mr.AddMovieMedia(ctx.Request.Context(), movieId, item)
+	if err != nil {
+		// the upload is only kept for a stored item
+		if body.File != nil {
+			removeMediaFile(repositories.MediaFilePath(body.Kind, item.URL))
+		}
+		mediaError(ctx, "UNABLE ADD MOVIE MEDIA", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.NewFullfilledResponse(
+		http.StatusCreated,
+		fmt.Sprintf("media w/ ID %d added to movie w/ ID %d", mediaId, movieId),
+	))
+}
+
+// HandleReorderMovieMedia godoc
+//
+//	@Summary		reorder gallery items (admin)
+//	@Description	items are placed in the order of ids, listed per kind
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"movie ID"
+//	@Param			body	body		models.MediaOrderBody	true	"media ids in their new order"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"media not found"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/media/order [patch]
+func (m *MovieHandler) HandleReorderMovieMedia(ctx *gin.Context) {
+	movieId, _, ok := mediaParams(ctx)
+	if !ok {
+		return
+	}
+	var body models.MediaOrderBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MEDIA ORDER BODY",
+			"ids must list at least one media ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	if !distinctIDs(body.IDs) {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MEDIA ORDER BODY",
+			"ids must list distinct media IDs",
+			errors.New("duplicate media ids"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	if err := m.mr.ReorderMovieMedia(ctx.Request.Context(), movieId, body.IDs); err != nil {
+		mediaError(ctx, "UNABLE REORDER MOVIE MEDIA", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("%d media of movie w/ ID %d reordered", len(body.IDs), movieId),
+	))
+}
+
+// HandleSetPrimaryMovieMedia godoc
+//
+//	@Summary		mark a gallery item primary (admin)
+//	@Description	the item becomes the primary of its kind, a primary poster or backdrop replaces the one shown in listings
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id			path		int	true	"movie ID"
+//	@Param			media_id	path		int	true	"media ID"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse	"media not found"
+//	@Failure		500			{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/media/{media_id}/primary [patch]
+func (m *MovieHandler) HandleSetPrimaryMovieMedia(ctx *gin.Context) {
+	movieId, mediaId, ok := mediaParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := m.mr.SetPrimaryMovieMedia(ctx.Request.Context(), movieId, mediaId); err != nil {
+		mediaError(ctx, "UNABLE SET PRIMARY MOVIE MEDIA", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("media w/ ID %d is now primary", mediaId),
+	))
+}
+
+// HandleDeleteMovieMedia godoc
+//
+//	@Summary		delete a gallery item (admin)
+//	@Description	the next item of the same kind becomes primary when the primary is deleted
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id			path		int	true	"movie ID"
+//	@Param			media_id	path		int	true	"media ID"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse	"media not found"
+//	@Failure		500			{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/media/{media_id} [delete]
+func (m *MovieHandler) HandleDeleteMovieMedia(ctx *gin.Context) {
+	movieId, mediaId, ok := mediaParams(ctx)
+	if !ok {
+		return
+	}
+
+	orphan, err := m.mr.DeleteMovieMedia(ctx.Request.Context(), movieId, mediaId)
+	if err != nil {
+		mediaError(ctx, "UNABLE DELETE MOVIE MEDIA", err)
+		return
+	}
+	if orphan != "" {
+		removeMediaFile(orphan)
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("media w/ ID %d deleted", mediaId),
+	))
+}
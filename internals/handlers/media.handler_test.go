@@ -0,0 +1,30 @@
+package handlers
+
+import "testing"
+
+func TestDistinctIDs(t *testing.T) {
+	tests := []struct {
+		ids  []int
+		want bool
+	}{
+		{nil, true},
+		{[]int{4}, true},
+		{[]int{3, 1, 2}, true},
+		{[]int{1, 2, 1}, false},
+		{[]int{5, 5}, false},
+	}
+	for _, tt := range tests {
+		if got := distinctIDs(tt.ids); got != tt.want {
+			t.Errorf("distinctIDs(%v) = %v, want %v", tt.ids, got, tt.want)
+		}
+	}
+}
+
+func TestDistinctIDsKeepsOrder(t *testing.T) {
+	// the reorder body is the new order, checking it must not sort it
+	ids := []int{3, 1, 2}
+	distinctIDs(ids)
+	if ids[0] != 3 || ids[1] != 1 || ids[2] != 2 {
+		t.Errorf("distinctIDs reordered its argument to %v", ids)
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/metgag/koda-weekly10/pkg"
+)
+
+type MetadataHandler struct {
+	mr       *repositories.MovieRepository
+	provider pkg.MetadataProvider
+}
+
+func NewMetadataHandler(mr *repositories.MovieRepository, provider pkg.MetadataProvider) *MetadataHandler {
+	return &MetadataHandler{mr: mr, provider: provider}
+}
+
+// HandleLookupMovies godoc
+//
+//	@Summary		look movies up in the metadata provider
+//	@Description	search the external catalogue (TMDB) for candidates to create movies from
+//	@Tags			admin
+//	@Produce		json
+//	@Param			q	query		string	true	"movie title"	example(pulp fiction)
+//	@Success		200	{object}	models.FulfilledResponse{result=[]pkg.MetadataCandidate}
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		502	{object}	models.ErrorResponse	"provider unavailable"
+//	@Security		BearerAuth
+//	@Router			/admin/movies/lookup [get]
+func (h *MetadataHandler) HandleLookupMovies(ctx *gin.Context) {
+	q := strings.TrimSpace(ctx.Query("q"))
+	if q == "" {
+		utils.LogCtxError(
+			ctx,
+			"EMPTY LOOKUP QUERY",
+			"q is required",
+			errors.New("empty lookup query"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	candidates, err := h.provider.Search(ctx.Request.Context(), q)
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"METADATA PROVIDER SEARCH FAILED",
+			"Metadata provider unavailable",
+			err,
+			http.StatusBadGateway,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		candidates,
+	))
+}
+
+// HandleCreateMovieFromProvider godoc
+//
+//	@Summary		create a movie from the metadata provider
+//	@Description	fetch overview, runtime, director, casts, genres and images of external_id and create the movie, the provider id is kept for later re-syncs
+//	@Tags			admin
+//	@Produce		json
+//	@Param			external_id	path		string	true	"provider movie id"	example(680)
+//	@Success		201			{object}	models.FulfilledResponse
+//	@Failure		404			{object}	models.ErrorResponse	"unknown external id"
+//	@Failure		409			{object}	models.ErrorResponse	"already imported"
+//	@Failure		422			{object}	models.ErrorResponse	"incomplete metadata"
+//	@Failure		502			{object}	models.ErrorResponse	"provider unavailable"
+//	@Security		BearerAuth
+//	@Router			/admin/movies/from-provider/{external_id} [post]
+func (h *MetadataHandler) HandleCreateMovieFromProvider(ctx *gin.Context) {
+	externalID := ctx.Param("external_id")
+
+	meta, err := h.provider.Movie(ctx.Request.Context(), externalID)
+	if err != nil {
+		if errors.Is(err, pkg.ErrMetadataNotFound) {
+			utils.LogCtxError(
+				ctx,
+				"UNKNOWN EXTERNAL MOVIE",
+				fmt.Sprintf("no %s movie w/ ID %s", h.provider.Name(), externalID),
+				err,
+				http.StatusNotFound,
+			)
+			return
+		}
+		utils.LogCtxError(
+			ctx,
+			"METADATA PROVIDER FETCH FAILED",
+			"Metadata provider unavailable",
+			err,
+			http.StatusBadGateway,
+		)
+		return
+	}
+
+	movieID, err := h.mr.CreateMovieFromMetadata(ctx.Request.Context(), h.provider.Name(), meta)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrMovieExists):
+			utils.LogCtxError(
+				ctx,
+				"EXTERNAL MOVIE ALREADY IMPORTED",
+				fmt.Sprintf("already imported as movie w/ ID %d", movieID),
+				err,
+				http.StatusConflict,
+			)
+		case errors.Is(err, repositories.ErrIncompleteMetadata):
+			utils.LogCtxError(
+				ctx,
+				"INCOMPLETE EXTERNAL MOVIE",
+				err.Error(),
+				err,
+				http.StatusUnprocessableEntity,
+			)
+		default:
+			utils.LogCtxError(
+				ctx,
+				"UNABLE CREATE MOVIE FROM PROVIDER",
+				"Internal server error",
+				err,
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.NewFullfilledResponse(
+		http.StatusCreated,
+		fmt.Sprintf("movie w/ ID %d created from %s %s", movieID, h.provider.Name(), externalID),
+	))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,9 +10,11 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/metgag/koda-weekly10/internals/models"
 	"github.com/metgag/koda-weekly10/internals/repositories"
 	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/metgag/koda-weekly10/pkg"
 )
 
 type MovieHandler struct {
@@ -26,19 +29,52 @@ func newMoviesResponse(result []models.MovieFilter, success bool, error string)
 	return models.MoviesResponse{Result: result, Success: success, Error: error}
 }
 
+// watchlisted returns which of ids the signed in user saved, nil for guests
+func (m *MovieHandler) watchlisted(ctx *gin.Context, ids []uint32) map[uint32]bool {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		return nil
+	}
+	user, _ := claims.(pkg.Claims)
+
+	saved, err := m.mr.WatchlistedMovieIDs(ctx.Request.Context(), user.UserID, ids)
+	if err != nil {
+		utils.PrintError("UNABLE TO GET WATCHLISTED MOVIES", 8, err)
+		return nil
+	}
+	return saved
+}
+
+// markWatchlisted sets the watchlisted flag of movies for signed in users
+func (m *MovieHandler) markWatchlisted(ctx *gin.Context, movies []models.MovieFilter) {
+	ids := make([]uint32, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.ID)
+	}
+	saved := m.watchlisted(ctx, ids)
+	if saved == nil {
+		return
+	}
+	for i := range movies {
+		isSaved := saved[movies[i].ID]
+		movies[i].Watchlisted = &isSaved
+	}
+}
+
 // HandleGetUpcomingMovies godoc
 //
 //	@Summary		Get upcoming movies
-//	@Description	Get list of movies with release date later than the current date
+//	@Description	Get list of movies releasing after today that have no showtime yet
 //	@Tags			movies
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	models.MovieResponse	"Upcoming movies fetched successfully"
-//	@Failure		404	{object}	models.MovieResponse	"No upcoming movies found"
-//	@Failure		500	{object}	models.MovieResponse	"Internal server error"
+//	@Param			lang	query		string					false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.MovieResponse	"Upcoming movies fetched successfully"
+//	@Failure		404		{object}	models.MovieResponse	"No upcoming movies found"
+//	@Failure		500		{object}	models.MovieResponse	"Internal server error"
 //	@Router			/movies/upcoming [get]
 func (m *MovieHandler) GetUpcomingMovies(ctx *gin.Context) {
-	movies, err := m.mr.GetUpcomingMovies(ctx.Request.Context())
+	movies, err := m.mr.GetUpcomingMovies(ctx.Request.Context(), utils.GetLanguage(ctx))
 	if err != nil {
 		utils.PrintError("UPCOMING MOVIES SERVER ERROR", 8, err)
 		ctx.JSON(http.StatusInternalServerError, newMoviesResponse(
@@ -54,6 +90,44 @@ func (m *MovieHandler) GetUpcomingMovies(ctx *gin.Context) {
 		))
 		return
 	}
+	m.markWatchlisted(ctx, movies)
+
+	ctx.JSON(http.StatusOK, newMoviesResponse(
+		movies, true, "",
+	))
+}
+
+// HandleGetNowShowingMovies godoc
+//
+//	@Summary		Get now showing movies
+//	@Description	Get movies with at least one upcoming showtime, optionally in a city
+//	@Tags			movies
+//	@Accept			json
+//	@Produce		json
+//	@Param			city	query		string					false	"city of the showtimes"							example(Jakarta)
+//	@Param			lang	query		string					false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.MoviesResponse	"Now showing movies fetched successfully"
+//	@Failure		404		{object}	models.MoviesResponse	"No movies showing"
+//	@Failure		500		{object}	models.MoviesResponse	"Internal server error"
+//	@Router			/movies/now-showing [get]
+func (m *MovieHandler) GetNowShowingMovies(ctx *gin.Context) {
+	movies, err := m.mr.GetNowShowingMovies(ctx.Request.Context(), ctx.Query("city"), utils.GetLanguage(ctx))
+	if err != nil {
+		utils.PrintError("NOW SHOWING MOVIES SERVER ERROR", 8, err)
+		ctx.JSON(http.StatusInternalServerError, newMoviesResponse(
+			nil, false, "server unable to get movies",
+		))
+		return
+	}
+
+	if len(movies) == 0 {
+		utils.PrintError("NOW SHOWING MOVIES DATA IS EMPTY", 8, nil)
+		ctx.JSON(http.StatusNotFound, newMoviesResponse(
+			nil, false, "no movies showing",
+		))
+		return
+	}
+	m.markWatchlisted(ctx, movies)
 
 	ctx.JSON(http.StatusOK, newMoviesResponse(
 		movies, true, "",
@@ -63,15 +137,30 @@ func (m *MovieHandler) GetUpcomingMovies(ctx *gin.Context) {
 // HandleGetPopularMovies godoc
 //
 //	@Summary		get popular movies handler func
-//	@Description	get list of movies where popularity higher than 40
+//	@Description	get movies ranked by tickets sold in the last window days, recent sales weigh more. popularity holds the score
 //	@Tags			movies
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	models.MovieResponse
-//	@Failure		500	{object}	models.MovieResponse	"internal server error"
+//	@Param			window	query		int		false	"days of sales counted, 1-30"					default(7)
+//	@Param			lang	query		string	false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.MovieResponse
+//	@Failure		400		{object}	models.MovieResponse	"invalid window"
+//	@Failure		500		{object}	models.MovieResponse	"internal server error"
 //	@Router			/movies/popular [get]
 func (m *MovieHandler) GetPopularMovies(ctx *gin.Context) {
-	movies, err := m.mr.GetPopularMovies(ctx.Request.Context())
+	var query models.PopularQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.PrintError("INVALID POPULAR QUERY", 8, err)
+		ctx.JSON(http.StatusBadRequest, newMoviesResponse(
+			nil, false, fmt.Sprintf("window must be 1-%d days", repositories.PopularityMaxWindow),
+		))
+		return
+	}
+	if query.Window == 0 {
+		query.Window = repositories.PopularityDefaultWindow
+	}
+
+	movies, err := m.mr.GetPopularMovies(ctx.Request.Context(), query.Window, utils.GetLanguage(ctx))
 	if err != nil {
 		utils.PrintError("POPULAR MOVIES SERVER ERROR", 8, err)
 		ctx.JSON(http.StatusInternalServerError, newMoviesResponse(
@@ -87,6 +176,7 @@ func (m *MovieHandler) GetPopularMovies(ctx *gin.Context) {
 		))
 		return
 	}
+	m.markWatchlisted(ctx, movies)
 
 	ctx.JSON(http.StatusOK, newMoviesResponse(
 		movies, true, "",
@@ -106,11 +196,12 @@ func newMovieResponse(res models.Movie, success bool, err string) models.MovieRe
 //	@Tags			movies
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		int						true	"movie ID"
-//	@Success		200	{object}	models.MovieResponse	"movie detail data"
-//	@Failure		400	{object}	models.MovieResponse	"invalid movie id"
-//	@Failure		404	{object}	models.MovieResponse	"movie not found"
-//	@Failure		500	{object}	models.MovieResponse	"internal server error"
+//	@Param			id		path		int						true	"movie ID"
+//	@Param			lang	query		string					false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.MovieResponse	"movie detail data"
+//	@Failure		400		{object}	models.MovieResponse	"invalid movie id"
+//	@Failure		404		{object}	models.MovieResponse	"movie not found"
+//	@Failure		500		{object}	models.MovieResponse	"internal server error"
 //	@Router			/movies/{id} [get]
 func (m *MovieHandler) GetMovieDetail(ctx *gin.Context) {
 	idParam, err := strconv.Atoi(ctx.Param("id"))
@@ -125,7 +216,17 @@ func (m *MovieHandler) GetMovieDetail(ctx *gin.Context) {
 		return
 	}
 
-	detail, err := m.mr.GetMovieDetail(ctx.Request.Context(), idParam)
+	detail, err := m.mr.GetMovieDetail(ctx.Request.Context(), idParam, utils.GetLanguage(ctx))
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.LogCtxError(
+			ctx,
+			"MOVIE NOT FOUND",
+			"Movie not found",
+			err,
+			http.StatusNotFound,
+		)
+		return
+	}
 	if err != nil {
 		utils.LogCtxError(
 			ctx,
@@ -137,6 +238,11 @@ func (m *MovieHandler) GetMovieDetail(ctx *gin.Context) {
 		return
 	}
 
+	if saved := m.watchlisted(ctx, []uint32{detail.ID}); saved != nil {
+		isSaved := saved[detail.ID]
+		detail.Watchlisted = &isSaved
+	}
+
 	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
 		http.StatusOK,
 		detail,
@@ -226,6 +332,171 @@ func (m *MovieHandler) HandleDeleteMovie(ctx *gin.Context) {
 	))
 }
 
+func newMovieBulkResponse(res models.MovieBulkResult, success bool, err string) models.MovieBulkResponse {
+	return models.MovieBulkResponse{Result: res, Success: success, Error: err}
+}
+
+func newMovieBulkResult(ids, affected []int) models.MovieBulkResult {
+	done := map[int]bool{}
+	for _, id := range affected {
+		done[id] = true
+	}
+	notFound := []int{}
+	for _, id := range ids {
+		if !done[id] {
+			notFound = append(notFound, id)
+		}
+	}
+	return models.MovieBulkResult{Affected: affected, NotFound: notFound}
+}
+
+// HandleGetTrashedMovies godoc
+//
+//	@Summary		get soft-deleted movies
+//	@Description	movies in the trash, newest deletion first, has_orders tells whether a purge would be refused
+//	@Tags			admin
+//	@Produce		json
+//	@Param			page	query		int	false	"page number"		example(1)
+//	@Param			limit	query		int	false	"items per page"	example(20)
+//	@Success		200		{object}	models.PaginatedResponse{result=[]models.TrashedMovie}
+//	@Security		BearerAuth
+//	@Router			/admin/movies/trash [get]
+func (m *MovieHandler) HandleGetTrashedMovies(ctx *gin.Context) {
+	page, limit, offset := utils.GetPagination(ctx, 20, 100)
+
+	movies, total, err := m.mr.GetTrashedMovies(ctx.Request.Context(), limit, offset)
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE TO GET TRASHED MOVIES",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK, movies, models.NewPageInfo(page, limit, total),
+	))
+}
+
+// movieIDs reads the movie ids from the :id param or, for bulk routes, the body
+func movieIDs(ctx *gin.Context) ([]int, bool) {
+	if param := ctx.Param("id"); param != "" {
+		id, err := strconv.Atoi(param)
+		if err != nil || id < 1 {
+			utils.PrintError("INVALID MOVIE ID", 12, err)
+			ctx.JSON(http.StatusBadRequest, newMovieBulkResponse(
+				models.MovieBulkResult{}, false, "invalid movie ID",
+			))
+			return nil, false
+		}
+		return []int{id}, true
+	}
+
+	var body models.MovieIDsBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.PrintError("INVALID MOVIE IDS", 12, err)
+		ctx.JSON(http.StatusBadRequest, newMovieBulkResponse(
+			models.MovieBulkResult{}, false, "ids must be a list of movie IDs",
+		))
+		return nil, false
+	}
+	return body.IDs, true
+}
+
+// HandleRestoreMovies godoc
+//
+//	@Summary		restore soft-deleted movies
+//	@Description	restore a movie by ID, or several with POST /admin/movies/restore and a list of ids
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"movie ID"
+//	@Param			body	body		models.MovieIDsBody	false	"ids for bulk restore"
+//	@Success		200		{object}	models.MovieBulkResponse
+//	@Failure		400		{object}	models.MovieBulkResponse
+//	@Failure		404		{object}	models.MovieBulkResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/restore [post]
+//	@Router			/admin/movies/restore [post]
+func (m *MovieHandler) HandleRestoreMovies(ctx *gin.Context) {
+	ids, ok := movieIDs(ctx)
+	if !ok {
+		return
+	}
+
+	restored, err := m.mr.RestoreMovies(ctx.Request.Context(), ids)
+	if err != nil {
+		utils.PrintError("UNABLE TO RESTORE MOVIES", 12, err)
+		ctx.JSON(http.StatusInternalServerError, newMovieBulkResponse(
+			models.MovieBulkResult{}, false, "server unable to restore movies",
+		))
+		return
+	}
+
+	result := newMovieBulkResult(ids, restored)
+	if len(restored) == 0 {
+		ctx.JSON(http.StatusNotFound, newMovieBulkResponse(
+			result, false, "no trashed movie found",
+		))
+		return
+	}
+	ctx.JSON(http.StatusOK, newMovieBulkResponse(result, true, ""))
+}
+
+// HandlePurgeMovies godoc
+//
+//	@Summary		permanently delete trashed movies
+//	@Description	purge a trashed movie by ID, or several with POST /admin/movies/purge and a list of ids. refused when any of them has orders
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"movie ID"
+//	@Param			body	body		models.MovieIDsBody	false	"ids for bulk purge"
+//	@Success		200		{object}	models.MovieBulkResponse
+//	@Failure		400		{object}	models.MovieBulkResponse
+//	@Failure		404		{object}	models.MovieBulkResponse
+//	@Failure		409		{object}	models.MovieBulkResponse	"movie has orders"
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/purge [delete]
+//	@Router			/admin/movies/purge [post]
+func (m *MovieHandler) HandlePurgeMovies(ctx *gin.Context) {
+	ids, ok := movieIDs(ctx)
+	if !ok {
+		return
+	}
+
+	purged, orphans, err := m.mr.PurgeMovies(ctx.Request.Context(), ids)
+	if err != nil {
+		if errors.Is(err, repositories.ErrMovieHasOrders) {
+			ctx.JSON(http.StatusConflict, newMovieBulkResponse(
+				models.MovieBulkResult{}, false, err.Error(),
+			))
+			return
+		}
+		utils.PrintError("UNABLE TO PURGE MOVIES", 12, err)
+		ctx.JSON(http.StatusInternalServerError, newMovieBulkResponse(
+			models.MovieBulkResult{}, false, "server unable to purge movies",
+		))
+		return
+	}
+
+	for _, orphan := range orphans {
+		removeMediaFile(orphan)
+	}
+
+	result := newMovieBulkResult(ids, purged)
+	if len(purged) == 0 {
+		ctx.JSON(http.StatusNotFound, newMovieBulkResponse(
+			result, false, "no trashed movie found",
+		))
+		return
+	}
+	ctx.JSON(http.StatusOK, newMovieBulkResponse(result, true, ""))
+}
+
 func newUpdateMovieResponse(success bool, res, err string) models.DeleteMovieResponse {
 	return models.DeleteMovieResponse{Success: success, Result: res, Error: err}
 }
@@ -241,6 +512,11 @@ func newUpdateMovieResponse(success bool, res, err string) models.DeleteMovieRes
 //	@Param			title			formData	string						false	"movie title"
 //	@Param			overview		formData	string						false	"movie description"
 //	@Param			runtime			formData	int							false	"movie duration (minutes)"
+//	@Param			age_rating		formData	string						false	"LSF age rating"	Enums(SU, 13+, 17+, 21+)
+//	@Param			director_name	formData	string						false	"director's full name"
+//	@Param			casts			formData	string						false	"comma separated cast names, replaces the cast"
+//	@Param			genres			formData	string						false	"comma separated genres, replaces the genres"
+//	@Param			credits			formData	string						false	"JSON models.CreditsBody, cast with characters and billing order and crew with jobs. each list given replaces its credits, cast wins over casts"
 //	@Param			backdrop_path	formData	file						false	"new backdrop file"
 //	@Param			poster_path		formData	file						false	"new poster file"
 //	@Success		200				{object}	models.UpdateMovieResponse	"movie updated successfully"
@@ -304,6 +580,12 @@ func (m *MovieHandler) HandleMovieUpdate(ctx *gin.Context) {
 
 	if err := m.mr.UpdateMovie(newBody, bDropName, posterName, ctx.Request.Context(), idParam); err != nil {
 		utils.PrintError("UNABLE TO UPDATE MOVIE TO DB", 8, err)
+		if errors.Is(err, repositories.ErrInvalidCredits) {
+			ctx.JSON(http.StatusBadRequest, newUpdateMovieResponse(
+				false, "", err.Error(),
+			))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, newUpdateMovieResponse(
 			false, "", "server unable to update movie",
 		))
@@ -317,27 +599,60 @@ func (m *MovieHandler) HandleMovieUpdate(ctx *gin.Context) {
 
 // HandleGetMovieWithGenrePageSearch godoc
 //
-//	@Summary	get movie with filter by name and genre with pagination
-//	@Tags		movies
-//	@Accept		json
-//	@Produce	json
-//	@Param		q		query		string	false	"search title by q"	example(pulp)
-//	@Param		page	query		int		false	"page number"		example(1)
-//	@Param		genre	query		string	false	"genre"				example(action)
-//	@Success	200		{object}	models.MovieResponse
-//	@Router		/movies/ [get]
+//	@Summary		get movie with filter, sort and pagination
+//	@Description	q searches title, director, cast and overview, results are ordered by relevance and highlighted unless sort is set
+//	@Tags			movies
+//	@Accept			json
+//	@Produce		json
+//	@Param			q			query		string	false	"search title, director, cast & overview by q"	example(pulp)
+//	@Param			page		query		int		false	"page number"									example(1)
+//	@Param			limit		query		int		false	"items per page, max 50"						example(12)
+//	@Param			genre		query		string	false	"genre"											example(action)
+//	@Param			genres		query		string	false	"comma separated genres"						example(action,drama)
+//	@Param			genre_match	query		string	false	"match any or all genres"						Enums(any, all)
+//	@Param			year_from	query		int		false	"release year from"								example(1990)
+//	@Param			year_to		query		int		false	"release year to"								example(2010)
+//	@Param			runtime_min	query		int		false	"minimum runtime in minutes"					example(90)
+//	@Param			runtime_max	query		int		false	"maximum runtime in minutes"					example(150)
+//	@Param			city		query		string	false	"only movies now showing in city"				example(Jakarta)
+//	@Param			sort		query		string	false	"sort by"										Enums(relevance, release_date, popularity, title, rating)
+//	@Param			order		query		string	false	"sort order"									Enums(asc, desc)
+//	@Param			lang		query		string	false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200			{object}	models.PaginatedResponse{result=[]models.MovieFilter}
+//	@Failure		400			{object}	models.MoviesResponse
+//	@Router			/movies/ [get]
 func (m *MovieHandler) HandleMovieWithGenrePageSearch(ctx *gin.Context) {
-	q := ctx.Query("q")
-	genreName := ctx.Query("genre")
-	page, err := strconv.Atoi(ctx.Query("page"))
-	if err != nil {
-		page = 1
+	var query models.MovieQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.PrintError("INVALID MOVIE QUERY", 8, err)
+		ctx.JSON(http.StatusBadRequest, newMoviesResponse(
+			nil, false, "invalid movie query",
+		))
+		return
+	}
+	if query.YearFrom != 0 && query.YearTo != 0 && query.YearFrom > query.YearTo {
+		ctx.JSON(http.StatusBadRequest, newMoviesResponse(
+			nil, false, "year_from must not be after year_to",
+		))
+		return
+	}
+	if query.RuntimeMin != 0 && query.RuntimeMax != 0 && query.RuntimeMin > query.RuntimeMax {
+		ctx.JSON(http.StatusBadRequest, newMoviesResponse(
+			nil, false, "runtime_min must not exceed runtime_max",
+		))
+		return
 	}
-	limit := 12
-	offset := (page - 1) * limit
 
-	movies, err := m.mr.GetMovieWithGenrePageSearch(ctx.Request.Context(), q, genreName, limit, offset)
+	page, limit, offset := utils.GetPagination(ctx, 12, 50)
+
+	movies, total, err := m.mr.GetMovieWithGenrePageSearch(ctx.Request.Context(), query, limit, offset, utils.GetLanguage(ctx))
 	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidGenre) {
+			ctx.JSON(http.StatusBadRequest, newMoviesResponse(
+				nil, false, err.Error(),
+			))
+			return
+		}
 		utils.LogCtxError(
 			ctx,
 			"UNABLE TO GET MOVIE WITH FILTER",
@@ -348,17 +663,38 @@ func (m *MovieHandler) HandleMovieWithGenrePageSearch(ctx *gin.Context) {
 		return
 	}
 
-	if len(movies) == 0 {
-		ctx.JSON(http.StatusOK, newMoviesResponse(
-			nil,
-			true,
-			"Nothing here but crickets",
+	m.markWatchlisted(ctx, movies)
+
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK, movies, models.NewPageInfo(page, limit, total),
+	))
+}
+
+func newSuggestionsResponse(result []models.Suggestion, success bool, err string) models.SuggestionsResponse {
+	return models.SuggestionsResponse{Result: result, Success: success, Error: err}
+}
+
+// HandleMovieSuggestions godoc
+//
+//	@Summary		autocomplete movie titles, directors and casts
+//	@Description	returns the 10 most popular completions of q from the redis prefix index
+//	@Tags			movies
+//	@Produce		json
+//	@Param			q		query		string	true	"text typed so far"								example(pul)
+//	@Param			lang	query		string	false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.SuggestionsResponse
+//	@Router			/movies/suggest [get]
+func (m *MovieHandler) HandleMovieSuggestions(ctx *gin.Context) {
+	suggestions, err := m.mr.GetSuggestions(ctx.Request.Context(), ctx.Query("q"), utils.GetLanguage(ctx))
+	if err != nil {
+		utils.PrintError("UNABLE TO GET SUGGESTIONS", 8, err)
+		ctx.JSON(http.StatusInternalServerError, newSuggestionsResponse(
+			nil, false, "server unable to get suggestions",
 		))
 		return
 	}
-	ctx.JSON(http.StatusOK, newMoviesResponse(
-		movies, true, "",
-	))
+
+	ctx.JSON(http.StatusOK, newSuggestionsResponse(suggestions, true, ""))
 }
 
 func newMovieSchedulesResponse(res models.MovieSchedule, success bool, err string) models.MovieSchedulesResponse {
@@ -398,6 +734,64 @@ func (m *MovieHandler) HandleGetMovieSchedule(ctx *gin.Context) {
 	))
 }
 
+func newMovieShowtimesResponse(res models.MovieShowtimes, success bool, err string) models.MovieShowtimesResponse {
+	return models.MovieShowtimesResponse{Result: res, Success: success, Error: err}
+}
+
+// HandleGetMovieShowtimes godoc
+//
+//	@Summary		get grouped movie showtimes
+//	@Description	upcoming showtimes of a movie grouped by date, location and cinema, with remaining seats and price
+//	@Tags			schedule
+//	@Produce		json
+//	@Param			id			path		int								true	"Movie ID"
+//	@Param			format		query		string							false	"Filter by screen format"	example(IMAX)
+//	@Param			audio		query		string							false	"Filter by audio language"	example(en)
+//	@Param			subtitle	query		string							false	"Filter by subtitle language"	example(id)
+//	@Success		200			{object}	models.MovieShowtimesResponse	"Showtimes retrieved successfully"
+//	@Failure		400	{object}	models.MovieShowtimesResponse	"invalid movie id"
+//	@Failure		404	{object}	models.MovieShowtimesResponse	"no upcoming showtimes"
+//	@Failure		500	{object}	models.MovieShowtimesResponse	"server error"
+//	@Router			/movies/{id}/showtimes [get]
+func (m *MovieHandler) HandleGetMovieShowtimes(ctx *gin.Context) {
+	movieId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.PrintError("INVALID MOVIE ID", 12, err)
+		ctx.JSON(http.StatusBadRequest, newMovieShowtimesResponse(
+			models.MovieShowtimes{}, false, "invalid movie ID",
+		))
+		return
+	}
+
+	var screening models.ScreeningFilter
+	if err := ctx.ShouldBindQuery(&screening); err != nil {
+		utils.PrintError("INVALID SCREENING FILTER", 12, err)
+		ctx.JSON(http.StatusBadRequest, newMovieShowtimesResponse(
+			models.MovieShowtimes{}, false, "invalid screening filter",
+		))
+		return
+	}
+
+	showtimes, err := m.mr.GetMovieShowtimes(ctx.Request.Context(), movieId, screening)
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("UNABLE TO GET MOVIE %d SHOWTIMES", movieId), 12, err)
+		ctx.JSON(http.StatusInternalServerError, newMovieShowtimesResponse(
+			models.MovieShowtimes{}, false, "server unable to get movie showtimes",
+		))
+		return
+	}
+	if len(showtimes.Dates) == 0 {
+		ctx.JSON(http.StatusNotFound, newMovieShowtimesResponse(
+			showtimes, false, fmt.Sprintf("no upcoming showtimes for movie ID %d", movieId),
+		))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newMovieShowtimesResponse(
+		showtimes, true, "",
+	))
+}
+
 func newMovieScheduleFilterResponse(res []models.MovieScheduleFilter, success bool, err string) models.MovieScheduleFilterResponse {
 	return models.MovieScheduleFilterResponse{Result: res, Success: success, Error: err}
 }
@@ -411,6 +805,9 @@ func newMovieScheduleFilterResponse(res []models.MovieScheduleFilter, success bo
 //	@Param			date		query	string	true	"Filter by date (YYYY-MM-DD)"
 //	@Param			time		query	int		true	"Filter by time (HHMM in 24h format, e.g. 2030)"
 //	@Param			location	query	int		true	"Filter by location ID"
+//	@Param			format		query	string	false	"Filter by screen format"
+//	@Param			audio		query	string	false	"Filter by audio language"
+//	@Param			subtitle	query	string	false	"Filter by subtitle language"
 //	@Produce		json
 //	@Success		200	{object}	models.MovieScheduleFilterResponse
 //	@Failure		404	{object}	models.MovieScheduleFilterResponse	"No matching schedule found"
@@ -421,12 +818,18 @@ func (m *MovieHandler) HandleGetMovieScheduleFilter(ctx *gin.Context) {
 	date := ctx.Query("date")
 	time, _ := strconv.Atoi(ctx.Query("time"))
 	loc, _ := strconv.Atoi(ctx.Query("location"))
+	screening := models.ScreeningFilter{
+		Format:   ctx.Query("format"),
+		Audio:    ctx.Query("audio"),
+		Subtitle: ctx.Query("subtitle"),
+	}
 
 	schedules, err := m.mr.GetMovieScheduleFilter(ctx.Request.Context(),
 		movieId,
 		time,
 		loc,
 		date,
+		screening,
 	)
 	if err != nil {
 		utils.LogCtxError(
@@ -470,10 +873,16 @@ func newCreateMovieResponse(res, err string, success bool) models.CreateMovieRes
 //	@Param			release_date	formData	string						true	"Release date in YYYY-MM-DD format"
 //	@Param			runtime			formData	int							false	"Runtime in minutes"
 //	@Param			overview		formData	string						false	"Movie overview"
-//	@Param			director_name	formData	string						true	"Director's full name"
+//	@Param			age_rating		formData	string						false	"LSF age rating, SU when empty"	Enums(SU, 13+, 17+, 21+)
+//	@Param			director_name	formData	string						false	"Director's full name, required without a crew director in credits"
 //	@Param			popularity		formData	number						true	"Popularity score (e.g. 78.5)"
 //	@Param			genres			formData	string						false	"JSON array of genre IDs as string: [12,14,18]"
 //	@Param			casts			formData	string						false	"JSON array of cast IDs as string: [1,2,3]"
+//	@Param			credits			formData	string						false	"JSON models.CreditsBody, cast with characters and billing order and crew with jobs, wins over casts and director_name"
+//	@Param			format			formData	string						false	"Screen format of the created schedules (2D, 3D, IMAX, 4DX)"
+//	@Param			audio_language	formData	string						false	"Audio language of the created schedules"
+//	@Param			subtitle_language	formData	string					false	"Subtitle language of the created schedules"
+//	@Param			price			formData	int							false	"Base ticket price of the created schedules"
 //	@Success		200				{object}	models.CreateMovieResponse	"Successfully created movie"
 //	@Failure		400				{object}	models.CreateMovieResponse	"Bad request, e.g. invalid input or file upload error"
 //	@Failure		500				{object}	models.CreateMovieResponse	"Internal server error, e.g. binding failure"
@@ -529,6 +938,12 @@ func (m *MovieHandler) HandleCreateMovie(ctx *gin.Context) {
 	res, err := m.mr.CreateMovie(body, bDropName, posterName, ctx.Request.Context())
 	if err != nil {
 		utils.PrintError("ERROR CREATE MOVIE", 20, err)
+		if errors.Is(err, repositories.ErrInvalidCredits) {
+			ctx.JSON(http.StatusBadRequest, newCreateMovieResponse(
+				"", err.Error(), false,
+			))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, newCreateMovieResponse(
 			"", "server error creating movie", true,
 		))
@@ -540,8 +955,253 @@ func (m *MovieHandler) HandleCreateMovie(ctx *gin.Context) {
 	))
 }
 
+func newMovieImportResponse(res models.MovieImportResult, success bool, err string) models.MovieImportResponse {
+	return models.MovieImportResponse{Result: res, Success: success, Error: err}
+}
+
+const maxImportFileSize = 2 << 20
+
+// HandleImportMovies godoc
+//
+//	@Summary		import movies from a CSV or JSON file
+//	@Description	CSV needs a header line, JSON an array of objects. columns: title, overview, runtime, release_date, director, casts, genres, poster_path, backdrop_path. casts and genres are comma separated, posters are optional URLs or filenames. valid rows are inserted in one transaction, invalid ones are reported per line
+//	@Tags			admin
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file	formData	file	true	"catalogue .csv or .json, max 2MB"
+//	@Param			dry_run	query		bool	false	"only validate the rows"
+//	@Success		200		{object}	models.MovieImportResponse	"dry run report"
+//	@Success		201		{object}	models.MovieImportResponse	"movies imported"
+//	@Failure		400		{object}	models.MovieImportResponse	"unreadable file or no valid rows"
+//	@Failure		500		{object}	models.MovieImportResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/import [post]
+func (m *MovieHandler) HandleImportMovies(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, newMovieImportResponse(
+			models.MovieImportResult{}, false, "dry_run must be true or false",
+		))
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		utils.PrintError("MISSING IMPORT FILE", 12, err)
+		ctx.JSON(http.StatusBadRequest, newMovieImportResponse(
+			models.MovieImportResult{}, false, "file is required",
+		))
+		return
+	}
+	if file.Size > maxImportFileSize {
+		ctx.JSON(http.StatusBadRequest, newMovieImportResponse(
+			models.MovieImportResult{}, false, "file must not exceed 2MB",
+		))
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		utils.PrintError("UNABLE TO OPEN IMPORT FILE", 12, err)
+		ctx.JSON(http.StatusInternalServerError, newMovieImportResponse(
+			models.MovieImportResult{}, false, "server unable to read file",
+		))
+		return
+	}
+	defer f.Close()
+
+	rows, err := utils.ParseMovieImport(file.Filename, f)
+	if err != nil {
+		utils.PrintError("UNABLE TO PARSE IMPORT FILE", 12, err)
+		ctx.JSON(http.StatusBadRequest, newMovieImportResponse(
+			models.MovieImportResult{}, false, err.Error(),
+		))
+		return
+	}
+
+	result, err := m.mr.ImportMovies(ctx.Request.Context(), rows, dryRun)
+	if err != nil {
+		utils.PrintError("UNABLE TO IMPORT MOVIES", 12, err)
+		ctx.JSON(http.StatusInternalServerError, newMovieImportResponse(
+			result, false, "server unable to import movies",
+		))
+		return
+	}
+
+	switch {
+	case dryRun:
+		ctx.JSON(http.StatusOK, newMovieImportResponse(result, len(result.Errors) == 0, ""))
+	case len(result.Imported) == 0:
+		ctx.JSON(http.StatusBadRequest, newMovieImportResponse(result, false, "no valid rows to import"))
+	default:
+		ctx.JSON(http.StatusCreated, newMovieImportResponse(result, true, ""))
+	}
+}
+
+// HandleCreateSchedules godoc
+//
+//	@Summary		create movie schedules
+//	@Description	create schedules of a movie for every given location (and its cinemas) and time
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CreateScheduleBody	true	"schedule body"
+//	@Success		201		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse	"invalid body or unknown format"
+//	@Failure		500		{object}	models.ErrorResponse	"internal server error"
+//	@Security		BearerAuth
+//	@Router			/admin/schedules [post]
+func (m *MovieHandler) HandleCreateSchedules(ctx *gin.Context) {
+	var body models.CreateScheduleBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE BIND SCHEDULE BODY",
+			"Invalid schedule body",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+	if _, err := time.Parse("2006-01-02", body.ScheduleDate); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID SCHEDULE DATE",
+			"schedule_date must be in YYYY-MM-DD format",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	inserted, err := m.mr.CreateSchedules(ctx.Request.Context(), body)
+	if err != nil {
+		if errors.Is(err, repositories.ErrMovieNotFound) {
+			utils.LogCtxError(
+				ctx,
+				"SCHEDULE FOR MISSING MOVIE",
+				"Unknown or deleted movie",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+		if utils.IsPgError(err, utils.PgForeignKeyViolation) {
+			utils.LogCtxError(
+				ctx,
+				"INVALID SCHEDULE REFERENCE",
+				"Unknown movie, time, location or format",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+		utils.LogCtxError(
+			ctx,
+			"UNABLE CREATE SCHEDULES",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.NewFullfilledResponse(
+		http.StatusCreated,
+		fmt.Sprintf("%d schedules created for movie w/ ID %d", inserted, body.MovieID),
+	))
+}
+
+// HandleUpdateSchedule godoc
+//
+//	@Summary		update a schedule
+//	@Description	update format, audio & subtitle language or base price of a schedule
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"schedule ID"
+//	@Param			request	body		models.UpdateScheduleBody	true	"fields to update"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse	"invalid body or unknown format"
+//	@Failure		404		{object}	models.ErrorResponse	"schedule not found"
+//	@Failure		500		{object}	models.ErrorResponse	"internal server error"
+//	@Security		BearerAuth
+//	@Router			/admin/schedules/{id} [patch]
+func (m *MovieHandler) HandleUpdateSchedule(ctx *gin.Context) {
+	scheduleId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID SCHEDULE ID",
+			"Invalid schedule ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	var body models.UpdateScheduleBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE BIND SCHEDULE BODY",
+			"Invalid schedule body",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	ctag, err := m.mr.UpdateSchedule(ctx.Request.Context(), body, scheduleId)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNothingToUpdate) {
+			utils.LogCtxError(
+				ctx,
+				"EMPTY SCHEDULE BODY",
+				"Nothing to update",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+		if utils.IsPgError(err, utils.PgForeignKeyViolation) {
+			utils.LogCtxError(
+				ctx,
+				"UNKNOWN SCHEDULE FORMAT",
+				"Unknown screen format",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+		utils.LogCtxError(
+			ctx,
+			"UNABLE UPDATE SCHEDULE",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	if ctag.RowsAffected() == 0 {
+		utils.LogCtxError(
+			ctx,
+			"SCHEDULE NOT FOUND",
+			fmt.Sprintf("No schedule w/ ID %d", scheduleId),
+			errors.New("schedule not found"),
+			http.StatusNotFound,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("schedule w/ ID %d updated succesfully", scheduleId),
+	))
+}
+
 func (m *MovieHandler) HandleGenres(ctx *gin.Context) {
-	genres, err := m.mr.GetGenres(ctx)
+	genres, err := m.mr.GetGenres(ctx.Request.Context(), utils.GetLanguage(ctx))
 	if err != nil {
 		utils.LogCtxError(
 			ctx,
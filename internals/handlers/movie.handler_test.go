@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+)
+
+func TestMarkWatchlistedGuest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/movies/upcoming", nil)
+
+	// guests carry no claims, so the repository is never asked
+	movies := []models.MovieFilter{{ID: 1}, {ID: 2}}
+	(&MovieHandler{}).markWatchlisted(ctx, movies)
+
+	for _, movie := range movies {
+		if movie.Watchlisted != nil {
+			t.Errorf("movie %d watchlisted = %v for a guest, want it left out", movie.ID, *movie.Watchlisted)
+		}
+	}
+}
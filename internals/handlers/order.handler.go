@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,11 +31,12 @@ func newOrderResponse(res string, success bool, err string) models.OrderResponse
 //	@Accept			json
 //	@Produce		json
 //
-//	@Param			order	body		models.CinemaOrder		true	"Order body"	example({"seats": [3, 4, 5]})
+//	@Param			order	body		models.CinemaOrderBody	true	"Order body"	example({"seats": [3, 4, 5]})
 //
 //	@Success		200		{object}	models.OrderResponse	"Order created successfully"
 //	@Failure		400		{object}	models.OrderResponse	"Invalid request payload"
 //	@Failure		401		{object}	models.OrderResponse	"Unauthorized: invalid or missing token"
+//	@Failure		403		{object}	models.OrderResponse	"Buyer is under the age rating or has no birthdate"
 //	@Failure		500		{object}	models.OrderResponse	"Internal server error"
 //	@Security		BearerAuth
 //	@Router			/orders [post]
@@ -54,13 +56,86 @@ func (o *OrderHandler) HandleCreateOrder(ctx *gin.Context) {
 
 	res, err := o.or.CreateOrder(ctx, body, user.UserID)
 	if err != nil {
-		utils.PrintError("UNABLE CREATE ORDER", 12, err)
-		ctx.JSON(http.StatusInternalServerError, newOrderResponse(
-			"", false, "server unable to create order",
+		orderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newOrderResponse(
+		res, true, "",
+	))
+}
+
+// orderError writes the response of a failed order
+func orderError(ctx *gin.Context, err error) {
+	if errors.Is(err, repositories.ErrScheduleNotFound) ||
+		errors.Is(err, repositories.ErrScheduleStarted) ||
+		errors.Is(err, repositories.ErrScheduleCancelled) {
+		utils.PrintError("UNABLE CREATE ORDER, SCHEDULE CLOSED", 12, err)
+		ctx.JSON(http.StatusBadRequest, newOrderResponse(
+			"", false, err.Error(),
+		))
+		return
+	}
+	if errors.Is(err, repositories.ErrUnderAge) ||
+		errors.Is(err, repositories.ErrBirthdateRequired) {
+		utils.PrintError("UNABLE CREATE ORDER, AGE CHECK FAILED", 12, err)
+		ctx.JSON(http.StatusForbidden, newOrderResponse(
+			"", false, err.Error(),
+		))
+		return
+	}
+	if errors.Is(err, repositories.ErrBuyerNotFound) {
+		utils.PrintError("UNABLE CREATE ORDER, BUYER NOT FOUND", 12, err)
+		ctx.JSON(http.StatusNotFound, newOrderResponse(
+			"", false, err.Error(),
+		))
+		return
+	}
+	utils.PrintError("UNABLE CREATE ORDER", 12, err)
+	ctx.JSON(http.StatusInternalServerError, newOrderResponse(
+		"", false, "server unable to create order",
+	))
+}
+
+// HandleCreateBoxOfficeOrder godoc
+//
+//	@Summary		create a box office order (admin)
+//	@Description	book on behalf of user_id, or the admin when omitted. the buyer must meet the age rating of the movie unless override_age_check is set, overrides are recorded on the order
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			order	body		models.BoxOfficeOrderBody	true	"Order body"
+//	@Success		200		{object}	models.OrderResponse		"Order created successfully"
+//	@Failure		400		{object}	models.OrderResponse		"Invalid request payload or schedule closed"
+//	@Failure		403		{object}	models.OrderResponse		"Buyer fails the age check"
+//	@Failure		404		{object}	models.OrderResponse		"Unknown user_id"
+//	@Failure		500		{object}	models.OrderResponse		"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/admin/orders [post]
+func (o *OrderHandler) HandleCreateBoxOfficeOrder(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	admin, _ := claims.(pkg.Claims)
+
+	var body models.BoxOfficeOrderBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.PrintError("UNABLE TO BIND BOX OFFICE ORDER BODY", 12, err)
+		ctx.JSON(http.StatusBadRequest, newOrderResponse(
+			"", false, "invalid order body",
 		))
 		return
 	}
 
+	uid := admin.UserID
+	if body.UID != nil {
+		uid = *body.UID
+	}
+
+	res, err := o.or.CreateBoxOfficeOrder(ctx, body.CinemaOrderBody, uid, admin.UserID, body.OverrideAgeCheck)
+	if err != nil {
+		orderError(ctx, err)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, newOrderResponse(
 		res, true, "",
 	))
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+// personID reads the :id param of a director, cast member, genre or collection
+func personID(ctx *gin.Context, what string) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		if err == nil {
+			err = errors.New("id must be positive")
+		}
+		utils.LogCtxError(
+			ctx,
+			"INVALID "+strings.ToUpper(what)+" ID",
+			"Invalid "+what+" ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// peopleError writes the response of a failed people or genre change
+func peopleError(ctx *gin.Context, head string, err error) {
+	msg, status := "Internal server error", http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrPersonNotFound),
+		errors.Is(err, repositories.ErrGenreNotFound):
+		msg, status = err.Error(), http.StatusNotFound
+	case errors.Is(err, repositories.ErrPersonInUse),
+		errors.Is(err, repositories.ErrGenreInUse),
+		errors.Is(err, repositories.ErrDuplicateName):
+		msg, status = err.Error(), http.StatusConflict
+	}
+	utils.LogCtxError(
+		ctx,
+		head,
+		msg,
+		err,
+		status,
+	)
+}
+
+// bindPersonBody binds the name of a person or genre
+func bindPersonBody(ctx *gin.Context, body any) bool {
+	if err := ctx.ShouldBindJSON(body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID NAME BODY",
+			"name is required",
+			err,
+			http.StatusBadRequest,
+		)
+		return false
+	}
+	return true
+}
+
+// HandleGetPeople godoc
+//
+//	@Summary		list directors or cast members (admin)
+//	@Description	sorted by name so duplicates like "Tom Hanks" and "tom hanks" sit together, movie_count counts their credits
+//	@Tags			admin
+//	@Produce		json
+//	@Param			q		query		string	false	"name contains"
+//	@Param			page	query		int		false	"page number"		example(1)
+//	@Param			limit	query		int		false	"items per page"	example(20)
+//	@Success		200		{object}	models.PaginatedResponse{result=[]models.PersonSummary}
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/directors [get]
+//	@Router			/admin/casts [get]
+func (m *MovieHandler) HandleGetPeople(kind string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		page, limit, offset := utils.GetPagination(ctx, 20, 100)
+
+		people, total, err := m.mr.GetPeople(ctx.Request.Context(), kind, strings.TrimSpace(ctx.Query("q")), limit, offset)
+		if err != nil {
+			utils.LogCtxError(
+				ctx,
+				"UNABLE GET PEOPLE",
+				"Internal server error",
+				err,
+				http.StatusInternalServerError,
+			)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+			http.StatusOK, people, models.NewPageInfo(page, limit, total),
+		))
+	}
+}
+
+// HandleCreatePerson godoc
+//
+//	@Summary		add a director or cast member (admin)
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.PersonBody	true	"name"
+//	@Success		201		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse	"name is already taken"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/directors [post]
+//	@Router			/admin/casts [post]
+func (m *MovieHandler) HandleCreatePerson(kind string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body models.PersonBody
+		if !bindPersonBody(ctx, &body) {
+			return
+		}
+
+		id, err := m.mr.CreatePerson(ctx.Request.Context(), kind, strings.TrimSpace(body.Name))
+		if err != nil {
+			peopleError(ctx, "UNABLE CREATE PERSON", err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, models.NewFullfilledResponse(
+			http.StatusCreated,
+			fmt.Sprintf("%s w/ ID %d created", kind, id),
+		))
+	}
+}
+
+// HandleRenamePerson godoc
+//
+//	@Summary		rename a director or cast member (admin)
+//	@Description	a director and the cast member they are linked to are renamed together
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"director or cast ID"
+//	@Param			body	body		models.PersonBody	true	"new name"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"person not found"
+//	@Failure		409		{object}	models.ErrorResponse	"name is already taken, merge them instead"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/directors/{id} [patch]
+//	@Router			/admin/casts/{id} [patch]
+func (m *MovieHandler) HandleRenamePerson(kind string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, ok := personID(ctx, kind)
+		if !ok {
+			return
+		}
+		var body models.PersonBody
+		if !bindPersonBody(ctx, &body) {
+			return
+		}
+
+		if err := m.mr.RenamePerson(ctx.Request.Context(), kind, id, strings.TrimSpace(body.Name)); err != nil {
+			peopleError(ctx, "UNABLE RENAME PERSON", err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+			http.StatusOK,
+			fmt.Sprintf("%s w/ ID %d renamed", kind, id),
+		))
+	}
+}
+
+// HandleDeletePerson godoc
+//
+//	@Summary		delete a director or cast member (admin)
+//	@Description	only people no movie credits can be deleted, merge credited duplicates instead
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"director or cast ID"
+//	@Success		200	{object}	models.FulfilledResponse
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		404	{object}	models.ErrorResponse	"person not found"
+//	@Failure		409	{object}	models.ErrorResponse	"person is still credited"
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/directors/{id} [delete]
+//	@Router			/admin/casts/{id} [delete]
+func (m *MovieHandler) HandleDeletePerson(kind string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, ok := personID(ctx, kind)
+		if !ok {
+			return
+		}
+
+		if err := m.mr.DeletePerson(ctx.Request.Context(), kind, id); err != nil {
+			peopleError(ctx, "UNABLE DELETE PERSON", err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+			http.StatusOK,
+			fmt.Sprintf("%s w/ ID %d deleted", kind, id),
+		))
+	}
+}
+
+// HandleMergePeople godoc
+//
+//	@Summary		merge duplicate directors or cast members (admin)
+//	@Description	every credit of ids moves to the person of the path, ids are deleted afterwards
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"director or cast ID kept"
+//	@Param			body	body		models.PeopleMergeBody	true	"IDs merged into it"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"person not found"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/directors/{id}/merge [post]
+//	@Router			/admin/casts/{id}/merge [post]
+func (m *MovieHandler) HandleMergePeople(kind string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, ok := personID(ctx, kind)
+		if !ok {
+			return
+		}
+		var body models.PeopleMergeBody
+		if err := ctx.ShouldBindJSON(&body); err != nil || slices.Contains(body.IDs, id) {
+			if err == nil {
+				err = errors.New("merged into itself")
+			}
+			utils.LogCtxError(
+				ctx,
+				"INVALID MERGE BODY",
+				"ids must list other IDs than the one kept",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+
+		if err := m.mr.MergePeople(ctx.Request.Context(), kind, id, body.IDs); err != nil {
+			peopleError(ctx, "UNABLE MERGE PEOPLE", err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+			http.StatusOK,
+			fmt.Sprintf("%d duplicates merged into %s w/ ID %d", len(body.IDs), kind, id),
+		))
+	}
+}
+
+// HandleGetPerson godoc
+//
+//	@Summary		get a person's filmography
+//	@Description	movies the person acted in, directed and crewed as writer, producer or composer, newest first. the ID is a cast ID, directors link to theirs with director_person_id
+//	@Tags			people
+//	@Produce		json
+//	@Param			id		path		int		true	"person ID"
+//	@Param			lang	query		string	false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.FulfilledResponse{result=models.Person}
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"person not found"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Router			/people/{id} [get]
+func (m *MovieHandler) HandleGetPerson(ctx *gin.Context) {
+	id, ok := personID(ctx, "person")
+	if !ok {
+		return
+	}
+
+	person, err := m.mr.GetPerson(ctx.Request.Context(), id, utils.GetLanguage(ctx))
+	if err != nil {
+		peopleError(ctx, "UNABLE GET PERSON", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		person,
+	))
+}
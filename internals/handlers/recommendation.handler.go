@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/metgag/koda-weekly10/pkg"
+)
+
+type RecommendationHandler struct {
+	rr *repositories.RecommendationRepository
+}
+
+func NewRecommendationHandler(rr *repositories.RecommendationRepository) *RecommendationHandler {
+	return &RecommendationHandler{rr: rr}
+}
+
+// HandleGetRecommendations godoc
+//
+//	@Summary		get movie recommendations
+//	@Description	movies with an upcoming showtime the user has not booked, scored by the genres, directors and casts of their past orders blended with popularity
+//	@Tags			users
+//	@Produce		json
+//	@Param			page	query		int		false	"page number"									example(1)
+//	@Param			limit	query		int		false	"items per page"								example(10)
+//	@Param			lang	query		string	false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.PaginatedResponse{result=[]models.Recommendation}
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/recommendations [get]
+func (h *RecommendationHandler) HandleGetRecommendations(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	page, limit, offset := utils.GetPagination(ctx, 10, 50)
+
+	recommendations, err := h.rr.GetRecommendations(ctx.Request.Context(), user.UserID, utils.GetLanguage(ctx))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE GET RECOMMENDATIONS",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	// the ranked list is cached whole, pages are cut from it
+	total := len(recommendations)
+	start, end := min(offset, total), min(offset+limit, total)
+
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK, recommendations[start:end], models.NewPageInfo(page, limit, total),
+	))
+}
@@ -0,0 +1,511 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/metgag/koda-weekly10/pkg"
+)
+
+type ReviewHandler struct {
+	rr     *repositories.ReviewRepository
+	filter *pkg.ProfanityFilter
+}
+
+func NewReviewHandler(rr *repositories.ReviewRepository, filter *pkg.ProfanityFilter) *ReviewHandler {
+	return &ReviewHandler{rr: rr, filter: filter}
+}
+
+// reviewParams reads the :id movie and optional :review_id params
+func reviewParams(ctx *gin.Context) (movieId, reviewId int, ok bool) {
+	movieId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MOVIE ID",
+			"Invalid movie ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return 0, 0, false
+	}
+	if param := ctx.Param("review_id"); param != "" {
+		reviewId, err = strconv.Atoi(param)
+		if err != nil {
+			utils.LogCtxError(
+				ctx,
+				"INVALID REVIEW ID",
+				"Invalid review ID",
+				err,
+				http.StatusBadRequest,
+			)
+			return 0, 0, false
+		}
+	}
+	return movieId, reviewId, true
+}
+
+// HandleGetMovieReviews godoc
+//
+//	@Summary		get movie reviews
+//	@Description	reviews of a movie with pagination, the aggregate rating is on the movie detail
+//	@Tags			reviews
+//	@Produce		json
+//	@Param			id		path		int		true	"movie ID"
+//	@Param			sort	query		string	false	"sort order"		Enums(newest, oldest, highest, lowest)
+//	@Param			page	query		int		false	"page number"		example(1)
+//	@Param			limit	query		int		false	"items per page"	example(10)
+//	@Success		200		{object}	models.PaginatedResponse{result=[]models.Review}
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Router			/movies/{id}/reviews [get]
+func (h *ReviewHandler) HandleGetMovieReviews(ctx *gin.Context) {
+	movieId, _, ok := reviewParams(ctx)
+	if !ok {
+		return
+	}
+	var query models.ReviewQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID REVIEW QUERY",
+			"sort must be newest, oldest, highest or lowest",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+	page, limit, offset := utils.GetPagination(ctx, 10, 50)
+
+	reviews, total, err := h.rr.GetMovieReviews(ctx.Request.Context(), movieId, query.Sort, limit, offset)
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE GET MOVIE REVIEWS",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK, reviews, models.NewPageInfo(page, limit, total),
+	))
+}
+
+// HandleCreateReview godoc
+//
+//	@Summary		review a movie
+//	@Description	rate a movie 1-5 with an optional text, only users with a paid order for a showtime that has passed may review, once per movie. reviews containing listed words are held for moderation
+//	@Tags			reviews
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"movie ID"
+//	@Param			body	body		models.ReviewBody	true	"review"
+//	@Success		201		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		403		{object}	models.ErrorResponse	"not a verified viewer"
+//	@Failure		404		{object}	models.ErrorResponse	"movie not found"
+//	@Failure		409		{object}	models.ErrorResponse	"already reviewed"
+//	@Security		BearerAuth
+//	@Router			/movies/{id}/reviews [post]
+func (h *ReviewHandler) HandleCreateReview(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	movieId, _, ok := reviewParams(ctx)
+	if !ok {
+		return
+	}
+	var body models.ReviewBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID REVIEW BODY",
+			"rating must be 1-5 and body at most 2000 characters",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	flagged := h.filter.Check(body.Body)
+	reviewId, err := h.rr.CreateReview(ctx.Request.Context(), user.UserID, movieId, body, flagged)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrMovieNotFound):
+			utils.LogCtxError(
+				ctx,
+				"REVIEW FOR MISSING MOVIE",
+				"Movie not found",
+				err,
+				http.StatusNotFound,
+			)
+		case errors.Is(err, repositories.ErrNotVerifiedViewer):
+			utils.LogCtxError(
+				ctx,
+				"REVIEW BY UNVERIFIED VIEWER",
+				err.Error(),
+				err,
+				http.StatusForbidden,
+			)
+		case utils.IsPgError(err, utils.PgUniqueViolation):
+			utils.LogCtxError(
+				ctx,
+				"DUPLICATE REVIEW",
+				"You already reviewed this movie",
+				err,
+				http.StatusConflict,
+			)
+		default:
+			utils.LogCtxError(
+				ctx,
+				"UNABLE CREATE REVIEW",
+				"Internal server error",
+				err,
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	msg := fmt.Sprintf("review w/ ID %d created", reviewId)
+	if len(flagged) > 0 {
+		msg += ", it will be visible once a moderator approves it"
+	}
+	ctx.JSON(http.StatusCreated, models.NewFullfilledResponse(
+		http.StatusCreated,
+		msg,
+	))
+}
+
+// HandleUpdateReview godoc
+//
+//	@Summary		edit own review
+//	@Description	a changed body is checked again and may send the review back to moderation
+//	@Tags			reviews
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int						true	"movie ID"
+//	@Param			review_id	path		int						true	"review ID"
+//	@Param			body		body		models.UpdateReviewBody	true	"fields to change"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse	"no such review of yours"
+//	@Security		BearerAuth
+//	@Router			/movies/{id}/reviews/{review_id} [patch]
+func (h *ReviewHandler) HandleUpdateReview(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	movieId, reviewId, ok := reviewParams(ctx)
+	if !ok {
+		return
+	}
+	var body models.UpdateReviewBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID REVIEW BODY",
+			"rating must be 1-5 and body at most 2000 characters",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	var flagged []string
+	if body.Body != nil {
+		flagged = h.filter.Check(*body.Body)
+	}
+	if err := h.rr.UpdateReview(ctx.Request.Context(), user.UserID, movieId, reviewId, body, flagged); err != nil {
+		if errors.Is(err, repositories.ErrReviewNotFound) {
+			utils.LogCtxError(
+				ctx,
+				"REVIEW NOT FOUND",
+				"Review not found",
+				err,
+				http.StatusNotFound,
+			)
+			return
+		}
+		utils.LogCtxError(
+			ctx,
+			"UNABLE UPDATE REVIEW",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("review w/ ID %d updated", reviewId),
+	))
+}
+
+// HandleDeleteReview godoc
+//
+//	@Summary		delete own review
+//	@Tags			reviews
+//	@Produce		json
+//	@Param			id			path		int	true	"movie ID"
+//	@Param			review_id	path		int	true	"review ID"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		404			{object}	models.ErrorResponse	"no such review of yours"
+//	@Security		BearerAuth
+//	@Router			/movies/{id}/reviews/{review_id} [delete]
+func (h *ReviewHandler) HandleDeleteReview(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	movieId, reviewId, ok := reviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.rr.DeleteReview(ctx.Request.Context(), user.UserID, movieId, reviewId); err != nil {
+		if errors.Is(err, repositories.ErrReviewNotFound) {
+			utils.LogCtxError(
+				ctx,
+				"REVIEW NOT FOUND",
+				"Review not found",
+				err,
+				http.StatusNotFound,
+			)
+			return
+		}
+		utils.LogCtxError(
+			ctx,
+			"UNABLE DELETE REVIEW",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("review w/ ID %d deleted", reviewId),
+	))
+}
+
+// HandleReportReview godoc
+//
+//	@Summary		report a review
+//	@Description	report another user's review, it is hidden and sent back to the moderation queue. a user files at most 5 reports a day
+//	@Tags			reviews
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int							true	"movie ID"
+//	@Param			review_id	path		int							true	"review ID"
+//	@Param			body		body		models.ReportReviewBody		false	"reason"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse	"own review"
+//	@Failure		404			{object}	models.ErrorResponse
+//	@Failure		409			{object}	models.ErrorResponse	"already reported"
+//	@Failure		429			{object}	models.ErrorResponse	"too many reports today"
+//	@Security		BearerAuth
+//	@Router			/movies/{id}/reviews/{review_id}/report [post]
+func (h *ReviewHandler) HandleReportReview(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	movieId, reviewId, ok := reviewParams(ctx)
+	if !ok {
+		return
+	}
+	var body models.ReportReviewBody
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			utils.LogCtxError(
+				ctx,
+				"INVALID REPORT BODY",
+				"reason must be at most 500 characters",
+				err,
+				http.StatusBadRequest,
+			)
+			return
+		}
+	}
+
+	if err := h.rr.ReportReview(ctx.Request.Context(), user.UserID, movieId, reviewId, body.Reason); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrReviewNotFound):
+			utils.LogCtxError(
+				ctx,
+				"REPORTED REVIEW NOT FOUND",
+				"Review not found",
+				err,
+				http.StatusNotFound,
+			)
+		case errors.Is(err, repositories.ErrOwnReview):
+			utils.LogCtxError(
+				ctx,
+				"REPORT OWN REVIEW",
+				err.Error(),
+				err,
+				http.StatusBadRequest,
+			)
+		case utils.IsPgError(err, utils.PgUniqueViolation):
+			utils.LogCtxError(
+				ctx,
+				"DUPLICATE REVIEW REPORT",
+				"You already reported this review",
+				err,
+				http.StatusConflict,
+			)
+		case errors.Is(err, repositories.ErrReportLimit):
+			utils.LogCtxError(
+				ctx,
+				"REVIEW REPORT LIMIT",
+				err.Error(),
+				err,
+				http.StatusTooManyRequests,
+			)
+		default:
+			utils.LogCtxError(
+				ctx,
+				"UNABLE REPORT REVIEW",
+				"Internal server error",
+				err,
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("review w/ ID %d reported", reviewId),
+	))
+}
+
+// HandleModerationQueue godoc
+//
+//	@Summary		review moderation queue
+//	@Description	reviews by status, oldest first, with flagged words and open reports
+//	@Tags			admin
+//	@Produce		json
+//	@Param			status	query		string	false	"review status, default pending"	Enums(pending, approved, rejected)
+//	@Param			page	query		int		false	"page number"						example(1)
+//	@Param			limit	query		int		false	"items per page"					example(20)
+//	@Success		200		{object}	models.PaginatedResponse{result=[]models.ModerationReview}
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/reviews [get]
+func (h *ReviewHandler) HandleModerationQueue(ctx *gin.Context) {
+	var query models.ModerationQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MODERATION QUERY",
+			"status must be pending, approved or rejected",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+	if query.Status == "" {
+		query.Status = models.ReviewPending
+	}
+	page, limit, offset := utils.GetPagination(ctx, 20, 100)
+
+	reviews, total, err := h.rr.GetModerationQueue(ctx.Request.Context(), query.Status, limit, offset)
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE GET MODERATION QUEUE",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK, reviews, models.NewPageInfo(page, limit, total),
+	))
+}
+
+// moderate approves or rejects the :review_id review
+func (h *ReviewHandler) moderate(ctx *gin.Context, status string) {
+	claims, _ := ctx.Get("claims")
+	admin, _ := claims.(pkg.Claims)
+
+	reviewId, err := strconv.Atoi(ctx.Param("review_id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID REVIEW ID",
+			"Invalid review ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	if err := h.rr.ModerateReview(ctx.Request.Context(), admin.UserID, reviewId, status); err != nil {
+		if errors.Is(err, repositories.ErrReviewNotFound) {
+			utils.LogCtxError(
+				ctx,
+				"MODERATED REVIEW NOT FOUND",
+				"Review not found",
+				err,
+				http.StatusNotFound,
+			)
+			return
+		}
+		utils.LogCtxError(
+			ctx,
+			"UNABLE MODERATE REVIEW",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("review w/ ID %d %s", reviewId, status),
+	))
+}
+
+// HandleApproveReview godoc
+//
+//	@Summary		approve a review
+//	@Description	publish a held review and resolve its reports
+//	@Tags			admin
+//	@Produce		json
+//	@Param			review_id	path		int	true	"review ID"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		404			{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/reviews/{review_id}/approve [post]
+func (h *ReviewHandler) HandleApproveReview(ctx *gin.Context) {
+	h.moderate(ctx, models.ReviewApproved)
+}
+
+// HandleRejectReview godoc
+//
+//	@Summary		reject a review
+//	@Description	hide a review for good and resolve its reports
+//	@Tags			admin
+//	@Produce		json
+//	@Param			review_id	path		int	true	"review ID"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		404			{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/reviews/{review_id}/reject [post]
+func (h *ReviewHandler) HandleRejectReview(ctx *gin.Context) {
+	h.moderate(ctx, models.ReviewRejected)
+}
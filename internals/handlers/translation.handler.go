@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+// translationLang reads the :lang param, the default language is edited on
+// the movie or genre itself
+func translationLang(ctx *gin.Context) (string, bool) {
+	lang := strings.ToLower(ctx.Param("lang"))
+	if lang == utils.DefaultLanguage || !slices.Contains(utils.Languages, lang) {
+		utils.LogCtxError(
+			ctx,
+			"INVALID TRANSLATION LANGUAGE",
+			fmt.Sprintf("lang must be one of %s", strings.Join(utils.Languages[1:], ", ")),
+			fmt.Errorf("unsupported language %q", lang),
+			http.StatusBadRequest,
+		)
+		return "", false
+	}
+	return lang, true
+}
+
+// translationError writes the response of a failed translation change
+func translationError(ctx *gin.Context, head string, err error) {
+	msg, status := "Internal server error", http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrMovieNotFound):
+		msg, status = "Movie not found", http.StatusNotFound
+	case errors.Is(err, repositories.ErrGenreNotFound),
+		errors.Is(err, repositories.ErrTranslationNotFound):
+		msg, status = err.Error(), http.StatusNotFound
+	}
+	utils.LogCtxError(
+		ctx,
+		head,
+		msg,
+		err,
+		status,
+	)
+}
+
+// HandleGetMovieTranslations godoc
+//
+//	@Summary		list the translations of a movie (admin)
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"movie ID"
+//	@Success		200	{object}	models.FulfilledResponse{result=[]models.MovieTranslation}
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		404	{object}	models.ErrorResponse	"movie not found"
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/translations [get]
+func (m *MovieHandler) HandleGetMovieTranslations(ctx *gin.Context) {
+	movieId, ok := personID(ctx, "movie")
+	if !ok {
+		return
+	}
+
+	translations, err := m.mr.GetMovieTranslations(ctx.Request.Context(), movieId)
+	if err != nil {
+		translationError(ctx, "UNABLE GET MOVIE TRANSLATIONS", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		translations,
+	))
+}
+
+// HandleSetMovieTranslation godoc
+//
+//	@Summary		translate a movie (admin)
+//	@Description	sets the title and overview shown for lang, fields left out keep their translation or the default language
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"movie ID"
+//	@Param			lang	path		string						true	"language"	Enums(id)
+//	@Param			body	body		models.MovieTranslationBody	true	"translated title and overview"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"movie not found"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/translations/{lang} [put]
+func (m *MovieHandler) HandleSetMovieTranslation(ctx *gin.Context) {
+	movieId, ok := personID(ctx, "movie")
+	if !ok {
+		return
+	}
+	lang, ok := translationLang(ctx)
+	if !ok {
+		return
+	}
+	var body models.MovieTranslationBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MOVIE TRANSLATION BODY",
+			"title or overview is required",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	if err := m.mr.SetMovieTranslation(ctx.Request.Context(), movieId, lang, body); err != nil {
+		translationError(ctx, "UNABLE SET MOVIE TRANSLATION", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("movie w/ ID %d translated to %s", movieId, lang),
+	))
+}
+
+// HandleDeleteMovieTranslation godoc
+//
+//	@Summary		delete a translation of a movie (admin)
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id		path		int		true	"movie ID"
+//	@Param			lang	path		string	true	"language"	Enums(id)
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"translation not found"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/movies/{id}/translations/{lang} [delete]
+func (m *MovieHandler) HandleDeleteMovieTranslation(ctx *gin.Context) {
+	movieId, ok := personID(ctx, "movie")
+	if !ok {
+		return
+	}
+	lang, ok := translationLang(ctx)
+	if !ok {
+		return
+	}
+
+	if err := m.mr.DeleteMovieTranslation(ctx.Request.Context(), movieId, lang); err != nil {
+		translationError(ctx, "UNABLE DELETE MOVIE TRANSLATION", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("%s translation of movie w/ ID %d deleted", lang, movieId),
+	))
+}
+
+// HandleGetGenreTranslations godoc
+//
+//	@Summary		list the translations of a genre (admin)
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"genre ID"
+//	@Success		200	{object}	models.FulfilledResponse{result=[]models.GenreTranslation}
+//	@Failure		400	{object}	models.ErrorResponse
+//	@Failure		404	{object}	models.ErrorResponse	"genre not found"
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/genres/{id}/translations [get]
+func (m *MovieHandler) HandleGetGenreTranslations(ctx *gin.Context) {
+	genreId, ok := personID(ctx, "genre")
+	if !ok {
+		return
+	}
+
+	translations, err := m.mr.GetGenreTranslations(ctx.Request.Context(), genreId)
+	if err != nil {
+		translationError(ctx, "UNABLE GET GENRE TRANSLATIONS", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		translations,
+	))
+}
+
+// HandleSetGenreTranslation godoc
+//
+//	@Summary		translate a genre (admin)
+//	@Description	the translated name is shown for lang and accepted by the genre filters
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"genre ID"
+//	@Param			lang	path		string						true	"language"	Enums(id)
+//	@Param			body	body		models.GenreTranslationBody	true	"translated name"
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"genre not found"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/genres/{id}/translations/{lang} [put]
+func (m *MovieHandler) HandleSetGenreTranslation(ctx *gin.Context) {
+	genreId, ok := personID(ctx, "genre")
+	if !ok {
+		return
+	}
+	lang, ok := translationLang(ctx)
+	if !ok {
+		return
+	}
+	var body models.GenreTranslationBody
+	if !bindPersonBody(ctx, &body) {
+		return
+	}
+
+	if err := m.mr.SetGenreTranslation(ctx.Request.Context(), genreId, lang, strings.TrimSpace(body.Name)); err != nil {
+		translationError(ctx, "UNABLE SET GENRE TRANSLATION", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("genre w/ ID %d translated to %s", genreId, lang),
+	))
+}
+
+// HandleDeleteGenreTranslation godoc
+//
+//	@Summary		delete a translation of a genre (admin)
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id		path		int		true	"genre ID"
+//	@Param			lang	path		string	true	"language"	Enums(id)
+//	@Success		200		{object}	models.FulfilledResponse
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse	"translation not found"
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/genres/{id}/translations/{lang} [delete]
+func (m *MovieHandler) HandleDeleteGenreTranslation(ctx *gin.Context) {
+	genreId, ok := personID(ctx, "genre")
+	if !ok {
+		return
+	}
+	lang, ok := translationLang(ctx)
+	if !ok {
+		return
+	}
+
+	if err := m.mr.DeleteGenreTranslation(ctx.Request.Context(), genreId, lang); err != nil {
+		translationError(ctx, "UNABLE DELETE GENRE TRANSLATION", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("%s translation of genre w/ ID %d deleted", lang, genreId),
+	))
+}
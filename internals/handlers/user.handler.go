@@ -73,6 +73,7 @@ func newUpdateResponse(res string, success bool, err string) models.UpdateRespon
 //	@Param			phone_number	formData	string					false	"Phone number (e.g., 08667728761)"
 //	@Param			point_count		formData	number					false	"User's point (e.g., 4.8)"
 //	@Param			avatar			formData	file					false	"Avatar image file"
+//	@Param			birthdate		formData	string					false	"Birthdate (YYYY-MM-DD), checked against movie age ratings"
 //	@Success		200				{object}	models.UpdateResponse	"User profile updated successfully"
 //	@Failure		400				{object}	models.UpdateResponse	"Invalid user ID or no user found"
 //	@Failure		500				{object}	models.UpdateResponse	"Server error while updating profile"
@@ -90,6 +91,14 @@ func (u *UserHandler) HandleUpdateUserInf(ctx *gin.Context) {
 		))
 		return
 	}
+	if newUserInf.Birthdate != nil {
+		if birthdate, _ := time.Parse("2006-01-02", *newUserInf.Birthdate); birthdate.After(time.Now()) {
+			ctx.JSON(http.StatusBadRequest, newUpdateResponse(
+				"", false, "birthdate can not be in the future",
+			))
+			return
+		}
+	}
 
 	// log.Println("AVAVAVAVA", newUserInf)
 
@@ -273,3 +282,67 @@ func (u *UserHandler) HandlePasswordEdit(ctx *gin.Context) {
 		fmt.Sprintf("succesfully update user's password w/ ID %d", user.UserID), "", true,
 	))
 }
+
+func newNotificationsResponse(res, err string, success bool) models.NotificationsResponse {
+	return models.NotificationsResponse{Result: res, Error: err, Success: success}
+}
+
+// HandleUserNotifications godoc
+//
+//	@Summary		get user notifications
+//	@Description	notifications such as cancelled showtimes or tickets opening for a watchlisted movie, newest first
+//	@Tags			users
+//	@Produce		json
+//	@Param			page	query		int	false	"page number"		example(1)
+//	@Param			limit	query		int	false	"items per page"	example(20)
+//	@Success		200		{object}	models.PaginatedResponse{result=[]models.Notification}
+//	@Failure		500		{object}	models.NotificationsResponse
+//	@Security		BearerAuth
+//	@Router			/users/notifications [get]
+func (u *UserHandler) HandleUserNotifications(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	page, limit, offset := utils.GetPagination(ctx, 20, 100)
+
+	notifications, total, err := u.ur.GetUserNotifications(ctx.Request.Context(), user.UserID, limit, offset)
+	if err != nil {
+		utils.PrintError("UNABLE TO GET USER NOTIFICATIONS", 12, err)
+		ctx.JSON(http.StatusInternalServerError, newNotificationsResponse(
+			"", "server unable to get notifications", false,
+		))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK, notifications, models.NewPageInfo(page, limit, total),
+	))
+}
+
+// HandleReadNotifications godoc
+//
+//	@Summary		mark notifications as read
+//	@Description	mark every unread notification of the user as read
+//	@Tags			users
+//	@Produce		json
+//	@Success		200	{object}	models.NotificationsResponse
+//	@Failure		500	{object}	models.NotificationsResponse
+//	@Security		BearerAuth
+//	@Router			/users/notifications/read [patch]
+func (u *UserHandler) HandleReadNotifications(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	ctag, err := u.ur.MarkNotificationsRead(ctx.Request.Context(), user.UserID)
+	if err != nil {
+		utils.PrintError("UNABLE TO READ USER NOTIFICATIONS", 12, err)
+		ctx.JSON(http.StatusInternalServerError, newNotificationsResponse(
+			"", "server unable to update notifications", false,
+		))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newNotificationsResponse(
+		fmt.Sprintf("%d notifications marked as read", ctag.RowsAffected()), "", true,
+	))
+}
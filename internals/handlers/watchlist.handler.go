@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/metgag/koda-weekly10/pkg"
+)
+
+type WatchlistHandler struct {
+	wr *repositories.WatchlistRepository
+}
+
+func NewWatchlistHandler(wr *repositories.WatchlistRepository) *WatchlistHandler {
+	return &WatchlistHandler{wr: wr}
+}
+
+// HandleGetWatchlist godoc
+//
+//	@Summary		get user watchlist
+//	@Description	movies saved by the user, latest first. tickets_open tells whether an upcoming showtime can be booked
+//	@Tags			users
+//	@Produce		json
+//	@Param			page	query		int		false	"page number"									example(1)
+//	@Param			limit	query		int		false	"items per page"								example(12)
+//	@Param			lang	query		string	false	"language, defaults to Accept-Language then en"	Enums(en, id)
+//	@Success		200		{object}	models.PaginatedResponse{result=[]models.WatchlistMovie}
+//	@Failure		500		{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/watchlist [get]
+func (h *WatchlistHandler) HandleGetWatchlist(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	page, limit, offset := utils.GetPagination(ctx, 12, 50)
+
+	movies, total, err := h.wr.GetWatchlist(ctx.Request.Context(), user.UserID, limit, offset, utils.GetLanguage(ctx))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE GET WATCHLIST",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewPaginatedResponse(
+		http.StatusOK, movies, models.NewPageInfo(page, limit, total),
+	))
+}
+
+// HandleAddToWatchlist godoc
+//
+//	@Summary		add a movie to the watchlist
+//	@Description	save a movie, the user is notified when its first showtime is scheduled. saving a movie twice is a no-op
+//	@Tags			users
+//	@Produce		json
+//	@Param			movie_id	path		int	true	"movie ID"
+//	@Success		201			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse	"movie not found"
+//	@Failure		500			{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/watchlist/{movie_id} [post]
+func (h *WatchlistHandler) HandleAddToWatchlist(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	movieId, err := strconv.Atoi(ctx.Param("movie_id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MOVIE ID",
+			"Invalid movie ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	ctag, err := h.wr.AddToWatchlist(ctx.Request.Context(), user.UserID, movieId)
+	if errors.Is(err, repositories.ErrMovieNotFound) {
+		utils.LogCtxError(
+			ctx,
+			"WATCHLIST MOVIE NOT FOUND",
+			"Movie not found",
+			err,
+			http.StatusNotFound,
+		)
+		return
+	}
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE ADD TO WATCHLIST",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	status, msg := http.StatusCreated, fmt.Sprintf("movie w/ ID %d added to watchlist", movieId)
+	if ctag.RowsAffected() == 0 {
+		status, msg = http.StatusOK, fmt.Sprintf("movie w/ ID %d is already in watchlist", movieId)
+	}
+	ctx.JSON(status, models.NewFullfilledResponse(status, msg))
+}
+
+// HandleRemoveFromWatchlist godoc
+//
+//	@Summary		remove a movie from the watchlist
+//	@Tags			users
+//	@Produce		json
+//	@Param			movie_id	path		int	true	"movie ID"
+//	@Success		200			{object}	models.FulfilledResponse
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse	"movie not in watchlist"
+//	@Failure		500			{object}	models.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/watchlist/{movie_id} [delete]
+func (h *WatchlistHandler) HandleRemoveFromWatchlist(ctx *gin.Context) {
+	claims, _ := ctx.Get("claims")
+	user, _ := claims.(pkg.Claims)
+
+	movieId, err := strconv.Atoi(ctx.Param("movie_id"))
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"INVALID MOVIE ID",
+			"Invalid movie ID",
+			err,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	ctag, err := h.wr.RemoveFromWatchlist(ctx.Request.Context(), user.UserID, movieId)
+	if err != nil {
+		utils.LogCtxError(
+			ctx,
+			"UNABLE REMOVE FROM WATCHLIST",
+			"Internal server error",
+			err,
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	if ctag.RowsAffected() == 0 {
+		utils.LogCtxError(
+			ctx,
+			"WATCHLIST ENTRY NOT FOUND",
+			"Movie is not in watchlist",
+			fmt.Errorf("no watchlist entry for movie %d", movieId),
+			http.StatusNotFound,
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.NewFullfilledResponse(
+		http.StatusOK,
+		fmt.Sprintf("movie w/ ID %d removed from watchlist", movieId),
+	))
+}
@@ -62,3 +62,32 @@ func ValidateToken(rdb *redis.Client) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// OptionalToken sets the claims when a valid token is sent but lets every
+// request through, for public routes that personalise their response
+func OptionalToken(rdb *redis.Client) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		splitToken := strings.Split(ctx.GetHeader("Authorization"), " ")
+		if len(splitToken) != 2 || !strings.EqualFold(splitToken[0], "Bearer") {
+			ctx.Next()
+			return
+		}
+		token := splitToken[1]
+
+		redisKey := fmt.Sprintf("archie:blacklist_%s", token)
+		result, err := rdb.Get(ctx, redisKey).Result()
+		if (err != nil && err != redis.Nil) || result == "1" {
+			ctx.Next()
+			return
+		}
+
+		var claims pkg.Claims
+		if err := claims.ValidateToken(token); err != nil {
+			ctx.Next()
+			return
+		}
+
+		ctx.Set("claims", claims)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/metgag/koda-weekly10/pkg"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestOptionalTokenLetsGuestsThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// no token reaches redis, so none is needed
+	r.GET("/movies", OptionalToken(nil), func(ctx *gin.Context) {
+		if _, exists := ctx.Get("claims"); exists {
+			t.Error("claims set without a bearer token")
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+
+	for _, header := range []string{"", "Bearer", "Basic dXNlcjpwYXNz", "Bearer a b"} {
+		req := httptest.NewRequest(http.MethodGet, "/movies", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent {
+			t.Errorf("Authorization %q: status %d, want %d", header, w.Code, http.StatusNoContent)
+		}
+	}
+}
+
+func TestOptionalTokenFailsClosedWithoutBlacklist(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("JWT_ISSUER", "archie")
+	token, err := pkg.NewJWTClaims(1, "user@mail.com", "", "user").GenAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// nothing listens here, so the blacklist cannot be checked
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	r.GET("/movies", OptionalToken(rdb), func(ctx *gin.Context) {
+		if _, exists := ctx.Get("claims"); exists {
+			t.Error("claims trusted while the blacklist was unreachable")
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/movies", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
@@ -1,16 +1,105 @@
 package models
 
 import (
+	"log"
+	"sync"
 	"time"
 )
 
 type CinemaSchedule struct {
 	ID         uint16    `db:"schedule_id" json:"schedule_id"`
+	MovieID    uint32    `db:"movie_id" json:"movie_id" example:"680"`
 	Title      string    `db:"title" json:"title" example:"Pulp Fiction"`
+	PosterPath *string   `db:"poster_path" json:"poster_path"`
 	Date       time.Time `db:"date" json:"date"`
-	Time       time.Time `db:"time" json:"time"`
+	Time       string    `db:"time" json:"time" example:"13:00"`
 	Location   string    `db:"location" json:"location" example:"Bogor"`
+	CinemaID   uint16    `db:"cinema_id" json:"cinema_id" example:"2"`
 	CinemaName string    `db:"cinema_name" json:"cinema_name" example:"ebv"`
+	CinemaImg  string    `db:"cinema_img" json:"cinema_img"`
+	Screening
+	LocalTime
+	Price int `db:"price" json:"price" example:"65000"`
+}
+
+// LocalTime is the absolute start of a schedule expressed in the
+// timezone of its location
+type LocalTime struct {
+	StartsAt  time.Time `json:"starts_at" example:"2025-10-21T19:30:00+08:00"`
+	Timezone  string    `json:"timezone" example:"Asia/Makassar"`
+	UTCOffset string    `json:"utc_offset" example:"+08:00"`
+}
+
+// locations caches the timezones loaded by NewLocalTime, a name that does
+// not load is logged once and shown in UTC
+var locations sync.Map
+
+func loadLocation(timezone string) *time.Location {
+	if loc, ok := locations.Load(timezone); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Printf("unable to load timezone %q, showing UTC: %s\n", timezone, err)
+		loc = time.UTC
+	}
+	locations.Store(timezone, loc)
+	return loc
+}
+
+func NewLocalTime(instant time.Time, timezone string) LocalTime {
+	loc := loadLocation(timezone)
+	local := instant.In(loc)
+
+	return LocalTime{
+		StartsAt:  local,
+		Timezone:  loc.String(),
+		UTCOffset: local.Format("-07:00"),
+	}
+}
+
+// Screening describes how a schedule is shown
+type Screening struct {
+	Format           string  `db:"format" json:"format" example:"IMAX"`
+	AudioLanguage    string  `db:"audio_language" json:"audio_language" example:"en"`
+	SubtitleLanguage *string `db:"subtitle_language" json:"subtitle_language" example:"id"`
+}
+
+type ScreeningFilter struct {
+	Format   string `form:"format"`
+	Audio    string `form:"audio"`
+	Subtitle string `form:"subtitle"`
+}
+
+type ScheduleFilter struct {
+	MovieID  int    `form:"movie_id"`
+	Date     string `form:"date"`
+	City     string `form:"city"`
+	CinemaID int    `form:"cinema_id"`
+	ScreeningFilter
+}
+
+type ScreenFormat struct {
+	Code      string `db:"code" json:"code" example:"IMAX"`
+	Surcharge int    `db:"surcharge" json:"surcharge" example:"35000"`
+}
+
+type CreateScheduleBody struct {
+	MovieID          int     `json:"movie_id" binding:"required"`
+	ScheduleDate     string  `json:"schedule_date" binding:"required" example:"2025-10-21"`
+	LocationID       []int   `json:"location" binding:"required,min=1"`
+	TimeID           []int   `json:"schedule_time" binding:"required,min=1"`
+	Format           string  `json:"format" example:"2D"`
+	AudioLanguage    string  `json:"audio_language" example:"en"`
+	SubtitleLanguage *string `json:"subtitle_language" example:"id"`
+	Price            int     `json:"price" example:"50000"`
+}
+
+type UpdateScheduleBody struct {
+	Format           *string `json:"format" db:"format" example:"3D"`
+	AudioLanguage    *string `json:"audio_language" db:"audio_language" example:"ja"`
+	SubtitleLanguage *string `json:"subtitle_language" db:"subtitle_language" example:"id"`
+	Price            *int    `json:"price" db:"price" example:"50000"`
 }
 
 type ScheduleResponse struct {
@@ -34,6 +123,7 @@ type CinemaAndTime struct {
 	CinemaName string `json:"cinema_name"`
 	Time       string `json:"time"`
 	CinemaImg  string `json:"cinema_img"`
+	LocalTime
 }
 
 type CinemaAndTimeResponse struct {
@@ -41,3 +131,29 @@ type CinemaAndTimeResponse struct {
 	Success bool          `json:"success"`
 	Error   string        `json:"error"`
 }
+
+type Cinema struct {
+	ID        uint16   `db:"id" json:"id" example:"2"`
+	Name      string   `db:"cinema_name" json:"name" example:"ebv"`
+	Img       string   `db:"cinema_img" json:"img"`
+	Address   *string  `db:"address" json:"address" example:"Jl. Pajajaran No. 121, Bogor"`
+	Latitude  *float64 `db:"latitude" json:"latitude" example:"-6.5971"`
+	Longitude *float64 `db:"longitude" json:"longitude" example:"106.8060"`
+	Locations []string `json:"locations"`
+}
+
+type NearbyCinema struct {
+	Cinema
+	DistanceKm float64    `json:"distance_km" example:"2.37"`
+	Showtimes  []Showtime `json:"showtimes,omitempty"`
+}
+
+type UpdateCinemaBody struct {
+	Address   *string  `json:"address" db:"address"`
+	Latitude  *float64 `json:"latitude" db:"latitude" binding:"required_with=Longitude,omitempty,latitude"`
+	Longitude *float64 `json:"longitude" db:"longitude" binding:"required_with=Latitude,omitempty,longitude"`
+}
+
+type LocationTimezoneBody struct {
+	Timezone string `json:"timezone" binding:"required" example:"Asia/Makassar"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Collection is a franchise or series, its movies in series order
+type Collection struct {
+	ID       int               `json:"id" example:"3"`
+	Name     string            `json:"name" example:"The Avengers Collection"`
+	Overview *string           `json:"overview"`
+	Movies   []CollectionMovie `json:"movies"`
+}
+
+// CollectionSummary is a collection of the admin list
+type CollectionSummary struct {
+	ID         int    `json:"id" example:"3"`
+	Name       string `json:"name" example:"The Avengers Collection"`
+	MovieCount int    `json:"movie_count" example:"4"`
+}
+
+// CollectionMovie is a movie of a collection, next_showtime is its first
+// upcoming showtime when it is showing
+type CollectionMovie struct {
+	ID           uint32     `json:"id" example:"24428"`
+	Title        string     `json:"title" example:"The Avengers"`
+	PosterPath   *string    `json:"poster_path"`
+	ReleaseDate  time.Time  `json:"release_date" example:"2012-04-25"`
+	AgeRating    string     `json:"age_rating" example:"13+"`
+	Position     int        `json:"position" example:"0"`
+	NowShowing   bool       `json:"now_showing" example:"true"`
+	NextShowtime *time.Time `json:"next_showtime"`
+}
+
+// MovieCollection is the collection of a movie detail with the other movies
+// of the series
+type MovieCollection struct {
+	ID       int               `json:"id" example:"3"`
+	Name     string            `json:"name" example:"The Avengers Collection"`
+	Siblings []CollectionMovie `json:"siblings"`
+}
+
+type CollectionBody struct {
+	Name     string  `json:"name" binding:"required,max=255" example:"The Avengers Collection"`
+	Overview *string `json:"overview" example:"Earth's mightiest heroes"`
+}
+
+// CollectionMoviesBody replaces the movies of a collection, in series order.
+// an empty list empties it
+type CollectionMoviesBody struct {
+	IDs []int `json:"ids" binding:"required,dive,min=1" example:"24428,99861"`
+}
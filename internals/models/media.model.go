@@ -0,0 +1,41 @@
+package models
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+const (
+	MediaPoster   = "poster"
+	MediaBackdrop = "backdrop"
+	MediaStill    = "still"
+	MediaTrailer  = "trailer"
+)
+
+// MovieMedia is an item of a movie gallery, images hold an uploaded filename
+// or a URL, trailers a video URL
+type MovieMedia struct {
+	ID        int       `json:"id" example:"1"`
+	Kind      string    `json:"kind" example:"trailer"` // poster, backdrop, still or trailer
+	URL       string    `json:"url" example:"https://www.youtube.com/watch?v=s7EdQ4FqbhY"`
+	Provider  *string   `json:"provider" example:"youtube"` // trailers only: youtube, vimeo or file
+	Language  *string   `json:"language" example:"en"`
+	Caption   *string   `json:"caption"`
+	Position  int       `json:"position" example:"0"`
+	IsPrimary bool      `json:"is_primary" example:"true"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MediaBody struct {
+	Kind     string                `form:"kind" binding:"required,oneof=poster backdrop still trailer"`
+	File     *multipart.FileHeader `form:"file"`
+	URL      string                `form:"url"`
+	Provider string                `form:"provider" binding:"omitempty,oneof=youtube vimeo file"`
+	Language string                `form:"language" binding:"omitempty,max=8"`
+	Caption  string                `form:"caption" binding:"omitempty,max=255"`
+	Primary  bool                  `form:"primary"`
+}
+
+type MediaOrderBody struct {
+	IDs []int `json:"ids" binding:"required,min=1" example:"3,1,2"`
+}
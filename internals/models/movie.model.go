@@ -14,9 +14,21 @@ type Movie struct {
 	Runtime      uint16    `db:"runtime" json:"runtime" example:"154" form:"runtime"`
 	Overview     string    `db:"overview" json:"overview" form:"overview"`
 	DirectorName string    `db:"director_name" json:"director_name" example:"Mick Jagger" form:"director_id"`
+	// the director's /people page
+	DirectorPersonID *int `json:"director_person_id" example:"31"`
 	// Popularity   *float32  `db:"popularity" json:"popularity" example:"17.246" form:"popularity"`
 	Genres []Genre `db:"genres" json:"genres"`
 	Casts  []Cast  `db:"casts" json:"cast"`
+	// cast with characters and crew with jobs, cast is the same people
+	Credits MovieCredits `json:"credits"`
+	// gallery of posters, backdrops, stills and trailers, primary first
+	Media []MovieMedia `json:"media"`
+	// the franchise of the movie with its other titles, null when it has none
+	Collection *MovieCollection `json:"collection"`
+	Rating
+	AgeRating string `json:"age_rating" example:"13+"`
+	// set only for signed in users
+	Watchlisted *bool `json:"watchlisted,omitempty"`
 }
 
 type MovieBody struct {
@@ -25,11 +37,13 @@ type MovieBody struct {
 	Runtime     *uint16  `form:"runtime" db:"runtime"`
 	ReleaseDate *string  `form:"release_date" db:"release_date"`
 	Popularity  *float32 `form:"popularity" db:"popularity"`
+	AgeRating   *string  `form:"age_rating" db:"age_rating" binding:"omitempty,oneof=SU 13+ 17+ 21+"`
 	// keep DirectorID if you want to update by ID directly
 	DirectorID   *uint32               `form:"director_id" db:"director_id"`
 	DirectorName *string               `form:"director_name"` // <-- NEW
 	Casts        *string               `form:"casts"`         // CSV: "Tom Hanks, Leonardo DiCaprio"
 	Genres       *string               `form:"genres"`        // CSV: "Drama, Thriller"
+	Credits      *string               `form:"credits"`       // JSON CreditsBody, replaces Casts
 	NewBackdrop  *multipart.FileHeader `form:"backdrop_path"`
 	NewPoster    *multipart.FileHeader `form:"poster_path"`
 }
@@ -62,6 +76,19 @@ type MoviesResponse struct {
 	Error   string        `json:"error"`
 }
 
+type Suggestion struct {
+	Type  string  `json:"type" example:"movie"` // movie, director or cast
+	ID    int     `json:"id" example:"1"`
+	Label string  `json:"label" example:"Pulp Fiction"`
+	Score float64 `json:"score" example:"87.5"`
+}
+
+type SuggestionsResponse struct {
+	Result  []Suggestion `json:"result"`
+	Success bool         `json:"success"`
+	Error   string       `json:"error"`
+}
+
 type MovieResponse struct {
 	Result  Movie  `json:"result"`
 	Success bool   `json:"success"`
@@ -97,6 +124,37 @@ type MovieFilter struct {
 	Overview    string    `json:"overview"`
 	Director    string    `json:"director"`
 	Casts       string    `json:"casts"`
+	Rating
+	AgeRating string `json:"age_rating" example:"13+"`
+	// set only when searching with q
+	Relevance *float32         `json:"relevance,omitempty" example:"0.87"`
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+	// set only for signed in users
+	Watchlisted *bool `json:"watchlisted,omitempty"`
+}
+
+type MovieQuery struct {
+	Q          string `form:"q"`
+	Genre      string `form:"genre"`
+	Genres     string `form:"genres"` // CSV: "action, drama"
+	GenreMatch string `form:"genre_match" binding:"omitempty,oneof=any all"`
+	YearFrom   int    `form:"year_from" binding:"omitempty,min=1800"`
+	YearTo     int    `form:"year_to" binding:"omitempty,min=1800"`
+	RuntimeMin int    `form:"runtime_min" binding:"omitempty,min=0"`
+	RuntimeMax int    `form:"runtime_max" binding:"omitempty,min=0"`
+	City       string `form:"city"` // only movies now showing in this city
+	Sort       string `form:"sort" binding:"omitempty,oneof=relevance release_date popularity title rating"`
+	Order      string `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+type PopularQuery struct {
+	Window int `form:"window" binding:"omitempty,min=1,max=30"` // days of ticket sales counted
+}
+
+type SearchHighlight struct {
+	Title         string   `json:"title" example:"<mark>Pulp</mark> Fiction"`
+	Overview      string   `json:"overview"`
+	MatchedFields []string `json:"matched_fields" example:"title,cast"`
 }
 
 type MovieFilterResponse struct {
@@ -105,6 +163,30 @@ type MovieFilterResponse struct {
 	Error   string        `json:"error"`
 }
 
+type TrashedMovie struct {
+	ID          int       `json:"id" example:"1"`
+	Title       string    `json:"title" example:"Pulp Fiction"`
+	PosterPath  *string   `json:"poster_path"`
+	ReleaseDate time.Time `json:"release_date"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	HasOrders   bool      `json:"has_orders"` // orders block a purge
+}
+
+type MovieIDsBody struct {
+	IDs []int `json:"ids" binding:"required,min=1,dive,min=1" example:"1,2"`
+}
+
+type MovieBulkResult struct {
+	Affected []int `json:"affected"`
+	NotFound []int `json:"not_found"`
+}
+
+type MovieBulkResponse struct {
+	Result  MovieBulkResult `json:"result"`
+	Success bool            `json:"success"`
+	Error   string          `json:"error"`
+}
+
 type DeleteMovieResponse struct {
 	Success bool
 	Result  string `example:"movie w/ ID 1 deleted succesfully"`
@@ -122,6 +204,10 @@ type Genre struct {
 	Name string `json:"name"`
 }
 
+type GenreBody struct {
+	Name string `json:"name" binding:"required,max=50" example:"Biography"`
+}
+
 type Cast struct {
 	ID   uint16 `json:"id"`
 	Name string `json:"name"`
@@ -139,6 +225,9 @@ type Schedule struct {
 	Location   string    `json:"location"`
 	Cinema     string    `json:"cinema"`
 	CinemaImg  string    `json:"cinema_img"`
+	Screening
+	LocalTime
+	Price int `json:"price"`
 }
 
 type MovieSchedulesResponse struct {
@@ -148,19 +237,62 @@ type MovieSchedulesResponse struct {
 }
 
 type CreateMovieBody struct {
-	DirectorName string                `form:"director_name" binding:"required"`
+	DirectorName string                `form:"director_name" binding:"required_without=Credits"`
 	Title        string                `form:"title" binding:"required"`
 	BackdropPath *multipart.FileHeader `form:"backdrop_path"`
 	PosterPath   *multipart.FileHeader `form:"poster_path"`
 	ReleaseDate  string                `form:"release_date" binding:"required"`
 	Runtime      uint16                `form:"runtime"`
 	Overview     string                `form:"overview"`
+	AgeRating    string                `form:"age_rating" binding:"omitempty,oneof=SU 13+ 17+ 21+"` // SU when empty
 	// Popularity   float32               `form:"popularity"`
-	Genres       string `form:"genres"`
-	Casts        string `form:"casts"`
-	LocationID   []int  `form:"location"`
-	ScheduleDate string `form:"schedule_date"`
-	TimeID       []int  `form:"schedule_time"`
+	Genres           string  `form:"genres"`
+	Casts            string  `form:"casts"`
+	Credits          string  `form:"credits"` // JSON CreditsBody, replaces Casts and DirectorName
+	LocationID       []int   `form:"location"`
+	ScheduleDate     string  `form:"schedule_date"`
+	TimeID           []int   `form:"schedule_time"`
+	Format           string  `form:"format"`
+	AudioLanguage    string  `form:"audio_language"`
+	SubtitleLanguage *string `form:"subtitle_language"`
+	Price            int     `form:"price"`
+}
+
+// ImportMovieRow is one movie of a catalogue import, casts and genres are
+// comma separated like the multipart create form
+type ImportMovieRow struct {
+	Line         int    `json:"-"`
+	Title        string `json:"title"`
+	Overview     string `json:"overview"`
+	Runtime      string `json:"runtime"`
+	ReleaseDate  string `json:"release_date"`
+	Director     string `json:"director"`
+	Casts        string `json:"casts"`
+	Genres       string `json:"genres"`
+	PosterPath   string `json:"poster_path"`   // URL or uploaded filename
+	BackdropPath string `json:"backdrop_path"` // URL or uploaded filename
+	// set when the CSV record could not be read, the row is invalid as a whole
+	ParseError string `json:"-"`
+}
+
+type ImportRowError struct {
+	Line   int      `json:"line" example:"3"` // file line for CSV, item number for JSON
+	Title  string   `json:"title"`
+	Errors []string `json:"errors"`
+}
+
+type MovieImportResult struct {
+	DryRun   bool             `json:"dry_run"`
+	Total    int              `json:"total"`
+	Valid    int              `json:"valid"`
+	Imported []uint32         `json:"imported"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+type MovieImportResponse struct {
+	Result  MovieImportResult `json:"result"`
+	Success bool              `json:"success"`
+	Error   string            `json:"error"`
 }
 
 type CreateMovieResponse struct {
@@ -179,6 +311,9 @@ type MovieScheduleFilter struct {
 	CinemaID   uint16 `json:"cinema_id"`
 	CinemaName string `json:"cinema_name"`
 	CinemaImg  string `json:"cinema_img"`
+	Screening
+	LocalTime
+	Price int `json:"price"`
 }
 
 type MovieScheduleFilterResponse struct {
@@ -186,3 +321,41 @@ type MovieScheduleFilterResponse struct {
 	Success bool                  `json:"success"`
 	Error   string                `json:"error"`
 }
+
+type MovieShowtimes struct {
+	MovieID uint32         `json:"movie_id"`
+	Dates   []ShowtimeDate `json:"dates"`
+}
+
+type ShowtimeDate struct {
+	Date      time.Time          `json:"date"`
+	Locations []ShowtimeLocation `json:"locations"`
+}
+
+type ShowtimeLocation struct {
+	LocationID uint16           `json:"location_id"`
+	Location   string           `json:"location" example:"Bogor"`
+	Cinemas    []ShowtimeCinema `json:"cinemas"`
+}
+
+type ShowtimeCinema struct {
+	CinemaID   uint16     `json:"cinema_id"`
+	CinemaName string     `json:"cinema_name" example:"ebv"`
+	CinemaImg  string     `json:"cinema_img"`
+	Showtimes  []Showtime `json:"showtimes"`
+}
+
+type Showtime struct {
+	ScheduleID     uint16 `json:"schedule_id"`
+	Time           string `json:"time" example:"13:00"`
+	RemainingSeats int    `json:"remaining_seats" example:"42"`
+	Screening
+	LocalTime
+	Price int `json:"price" example:"50000"`
+}
+
+type MovieShowtimesResponse struct {
+	Result  MovieShowtimes `json:"result"`
+	Success bool           `json:"success"`
+	Error   string         `json:"error"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// notification kinds, clients may group or style notifications by them
+const (
+	NotificationScheduleCancelled = "schedule_cancelled"
+	NotificationTicketsOpen       = "tickets_open"
+)
+
+type Notification struct {
+	ID        int        `json:"id" example:"1"`
+	Kind      string     `json:"kind" example:"schedule_cancelled"`
+	OrderID   *int       `json:"order_id" example:"12"`
+	MovieID   *int       `json:"movie_id" example:"680"`
+	Title     string     `json:"title" example:"Showtime cancelled"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+type NotificationsResponse struct {
+	Result  string `json:"result"`
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
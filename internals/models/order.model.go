@@ -12,7 +12,10 @@ type OrderHistory struct {
 	Time       string     `db:"time" json:"time"`
 	CinemaName string     `db:"cinema_name" json:"cinema_name" example:"ebv"`
 	PaidAt     *time.Time `json:"paid_at"`
-	Seats      []string   `json:"seats"`
+	// set when the showtime was cancelled
+	CancelledAt *time.Time `json:"cancelled_at"`
+	Seats       []string   `json:"seats"`
+	LocalTime
 }
 
 // type CinemaOrder struct {
@@ -33,6 +36,13 @@ type CinemaOrderBody struct {
 	PaidAt        *bool   `json:"paid_at"`
 }
 
+// BoxOfficeOrderBody books for user_id, or the admin when omitted
+type BoxOfficeOrderBody struct {
+	CinemaOrderBody
+	// sell even when the buyer fails the age check of the movie
+	OverrideAgeCheck bool `json:"override_age_check" example:"false"`
+}
+
 type OrderResponse struct {
 	Result  string
 	Success bool
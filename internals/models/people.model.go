@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// PersonSummary is a director or cast member of the admin lists
+type PersonSummary struct {
+	ID         int    `json:"id" example:"31"`
+	Name       string `json:"name" example:"Tom Hanks"`
+	MovieCount int    `json:"movie_count" example:"4"` // trashed movies included
+}
+
+type PersonBody struct {
+	Name string `json:"name" binding:"required,max=255" example:"Tom Hanks"`
+}
+
+type PeopleMergeBody struct {
+	IDs []int `json:"ids" binding:"required,min=1,dive,min=1" example:"12,40"`
+}
+
+// Person is the public page of a cast member, with the movies they directed
+// when they are a director as well and the rest of their crew work
+type Person struct {
+	ID       int            `json:"id" example:"31"`
+	Name     string         `json:"name" example:"Tom Hanks"`
+	Acted    []PersonCredit `json:"acted"`
+	Directed []PersonCredit `json:"directed"`
+	Crew     []PersonCredit `json:"crew"`
+}
+
+type PersonCredit struct {
+	ID          uint32    `json:"id" example:"13"`
+	Title       string    `json:"title" example:"Forrest Gump"`
+	PosterPath  *string   `json:"poster_path"`
+	ReleaseDate time.Time `json:"release_date" example:"1994-06-23"`
+	AgeRating   string    `json:"age_rating" example:"13+"`
+	Rating
+	Character *string `json:"character,omitempty" example:"Forrest Gump"` // acted only
+	Job       string  `json:"job,omitempty" example:"producer"`           // crew only
+}
+
+const (
+	CrewDirector = "director"
+	CrewWriter   = "writer"
+	CrewProducer = "producer"
+	CrewComposer = "composer"
+)
+
+// CreditsBody is the JSON credits field of the movie forms. a list replaces
+// the credits of its kind, an omitted list keeps them
+type CreditsBody struct {
+	Cast []CastCreditBody `json:"cast"`
+	Crew []CrewCreditBody `json:"crew"`
+}
+
+// CastCreditBody credits a person by person_id, or by name when it is 0,
+// billed in list order unless order is set
+type CastCreditBody struct {
+	PersonID  int    `json:"person_id" example:"31"`
+	Name      string `json:"name" example:"Tom Hanks"`
+	Character string `json:"character" example:"Forrest Gump"`
+	Order     *int   `json:"order" example:"1"`
+}
+
+type CrewCreditBody struct {
+	PersonID int    `json:"person_id" example:"40"`
+	Name     string `json:"name" example:"Robert Zemeckis"`
+	Job      string `json:"job" example:"director"` // director, writer, producer or composer
+}
+
+// MovieCredits is the cast by billing order and the crew by job
+type MovieCredits struct {
+	Cast []CastCredit `json:"cast"`
+	Crew []CrewCredit `json:"crew"`
+}
+
+type CastCredit struct {
+	PersonID  int     `json:"person_id" example:"31"`
+	Name      string  `json:"name" example:"Tom Hanks"`
+	Character *string `json:"character" example:"Forrest Gump"`
+	Order     *int    `json:"order" example:"1"`
+}
+
+type CrewCredit struct {
+	PersonID int    `json:"person_id" example:"40"`
+	Name     string `json:"name" example:"Robert Zemeckis"`
+	Job      string `json:"job" example:"director"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Recommendation struct {
+	ID          uint32    `json:"id" example:"680"`
+	Title       string    `json:"title" example:"Pulp Fiction"`
+	PosterPath  *string   `json:"poster_path"`
+	ReleaseDate time.Time `json:"release_date" example:"1994-09-10"`
+	Genres      []Genre   `json:"genres"`
+	Rating
+	AgeRating string `json:"age_rating" example:"13+"`
+	// share of the user's booked genres, directors and casts the movie matches, 0-1
+	Affinity float64 `json:"affinity" example:"0.62"`
+	Score    float64 `json:"score" example:"0.71"`
+}
@@ -19,3 +19,34 @@ type ErrorResponse struct {
 	Status  int    `json:"status"`
 	Error   string `json:"error"`
 }
+
+type PageInfo struct {
+	Page       int `json:"page" example:"1"`
+	Limit      int `json:"limit" example:"20"`
+	Total      int `json:"total" example:"57"`
+	TotalPages int `json:"total_pages" example:"3"`
+}
+
+func NewPageInfo(page, limit, total int) PageInfo {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	return PageInfo{Page: page, Limit: limit, Total: total, TotalPages: totalPages}
+}
+
+type PaginatedResponse struct {
+	Success bool        `json:"success"`
+	Status  int         `json:"status"`
+	Result  interface{} `json:"result"`
+	PageInfo
+}
+
+func NewPaginatedResponse(statusCode int, result interface{}, page PageInfo) PaginatedResponse {
+	return PaginatedResponse{
+		Success:  true,
+		Status:   statusCode,
+		Result:   result,
+		PageInfo: page,
+	}
+}
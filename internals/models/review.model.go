@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Rating is the aggregate of a movie's reviews
+type Rating struct {
+	Average float32 `json:"rating" example:"4.25"`
+	Count   int     `json:"rating_count" example:"12"`
+}
+
+type Review struct {
+	ID        int       `json:"id" example:"1"`
+	MovieID   int       `json:"movie_id" example:"680"`
+	UserID    int       `json:"user_id" example:"3"`
+	UserName  string    `json:"user_name" example:"Jane D."`
+	Avatar    *string   `json:"avatar"`
+	Rating    int       `json:"rating" example:"5"`
+	Body      string    `json:"body" example:"Still holds up."`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ReviewBody struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5" example:"5"`
+	Body   string `json:"body" binding:"max=2000" example:"Still holds up."`
+}
+
+type UpdateReviewBody struct {
+	Rating *int    `json:"rating" binding:"omitempty,min=1,max=5" example:"4"`
+	Body   *string `json:"body" binding:"omitempty,max=2000"`
+}
+
+type ReviewQuery struct {
+	Sort string `form:"sort" binding:"omitempty,oneof=newest oldest highest lowest"`
+}
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+type ReviewReport struct {
+	UserID    int       `json:"user_id" example:"7"`
+	Reason    string    `json:"reason" example:"spoilers"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModerationReview is a review as seen in the admin moderation queue
+type ModerationReview struct {
+	Review
+	MovieTitle   string         `json:"movie_title" example:"Pulp Fiction"`
+	Status       string         `json:"status" example:"pending"`
+	FlaggedWords []string       `json:"flagged_words"`
+	Reports      []ReviewReport `json:"reports"`
+}
+
+type ModerationQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
+
+type ReportReviewBody struct {
+	Reason string `json:"reason" binding:"max=500" example:"spoilers"`
+}
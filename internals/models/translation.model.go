@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// MovieTranslation is the title and overview of a movie in another language,
+// a null field shows the default language
+type MovieTranslation struct {
+	Language  string    `json:"language" example:"id"`
+	Title     *string   `json:"title" example:"Kisah Cinta Forrest"`
+	Overview  *string   `json:"overview"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MovieTranslationBody sets the translated fields given, the others are kept
+type MovieTranslationBody struct {
+	Title    *string `json:"title" binding:"required_without=Overview,omitempty,max=255" example:"Kisah Cinta Forrest"`
+	Overview *string `json:"overview" binding:"required_without=Title"`
+}
+
+type GenreTranslation struct {
+	Language string `json:"language" example:"id"`
+	Name     string `json:"name" example:"Petualangan"`
+}
+
+type GenreTranslationBody struct {
+	Name string `json:"name" binding:"required,max=50" example:"Petualangan"`
+}
@@ -1,6 +1,9 @@
 package models
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+)
 
 type UserInf struct {
 	UID         uint16  `db:"user_id" json:"user_id"`
@@ -9,7 +12,9 @@ type UserInf struct {
 	PhoneNumber *string `db:"phone_number" json:"phone_number" binding:"min=10.numeric" example:"08224422765"`
 	PointCount  float32 `db:"point_count" json:"point_count" example:"4.2"`
 	Avatar      *string `db:"avatar" json:"avatar"`
-	Role        string  `json:"role"`
+	// used for the LSF age check on orders
+	Birthdate *time.Time `db:"birthdate" json:"birthdate" example:"2001-08-17T00:00:00Z"`
+	Role      string     `json:"role"`
 }
 
 type UserinfResponse struct {
@@ -24,6 +29,7 @@ type NewInf struct {
 	PhoneNumber *string               `db:"phone_number" form:"phone_number" example:"08667728761"`
 	PointCount  *float32              `db:"point_count" form:"point_count" example:"4.8"`
 	Avatar      *multipart.FileHeader `db:"avatar" form:"avatar"`
+	Birthdate   *string               `db:"birthdate" form:"birthdate" binding:"omitempty,datetime=2006-01-02" example:"2001-08-17"`
 }
 
 // type NewInf struct {
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type WatchlistMovie struct {
+	ID          uint32    `json:"id" example:"680"`
+	Title       string    `json:"title" example:"Pulp Fiction"`
+	PosterPath  *string   `json:"poster_path"`
+	ReleaseDate time.Time `json:"release_date" example:"1994-09-10"`
+	AgeRating   string    `json:"age_rating" example:"13+"`
+	Genres      []Genre   `json:"genres"`
+	TicketsOpen bool      `json:"tickets_open" example:"false"` // an upcoming showtime can be booked
+	AddedAt     time.Time `json:"added_at"`
+}
@@ -2,23 +2,117 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/redis/go-redis/v9"
 )
 
 type CinemaRepository struct {
 	dbpool *pgxpool.Pool
+	rdb    *redis.Client
 }
 
-func NewCinemaRepository(dbpool *pgxpool.Pool) *CinemaRepository {
-	return &CinemaRepository{dbpool: dbpool}
+func NewCinemaRepository(dbpool *pgxpool.Pool, rdb *redis.Client) *CinemaRepository {
+	return &CinemaRepository{dbpool: dbpool, rdb: rdb}
 }
 
-func (c *CinemaRepository) GetSchedule(ctx context.Context) ([]models.CinemaSchedule, error) {
-	sql := `
-		SELECT 
-			s.id "schedule_id", m.title, s.date, t.time, l.location, c.name "cinema_name"
+// scheduleStartsAtExpr is the absolute start of schedule s, its naive show
+// date & time are read in the timezone of location l joined with time t
+const scheduleStartsAtExpr = "((s.show_date + t.show_time::time) AT TIME ZONE l.timezone)"
+
+// liveScheduleCond hides cancelled schedules and those of soft-deleted
+// movies, it needs schedule s joined with movies m
+const liveScheduleCond = "s.cancelled_at IS NULL AND m.deleted_at IS NULL"
+
+// upcomingScheduleCond keeps only schedules that have not started yet
+const upcomingScheduleCond = scheduleStartsAtExpr + " > NOW()"
+
+// schedulePriceExpr is the ticket price of a schedule after its format
+// surcharge, the query must join screen_formats as f
+const schedulePriceExpr = "s.price + f.surcharge"
+
+// todayRemainingCond keeps schedules of today, in the location's own
+// calendar, that have not started yet
+const todayRemainingCond = "s.show_date = (NOW() AT TIME ZONE l.timezone)::date AND " + upcomingScheduleCond
+
+// haversineExpr is the great-circle distance in km between ($1, $2) and
+// cinema c, LEAST guards acos from rounding errors above 1
+const haversineExpr = `
+	6371 * ACOS(LEAST(1,
+		COS(RADIANS($1)) * COS(RADIANS(c.latitude)) * COS(RADIANS(c.longitude) - RADIANS($2)) +
+		SIN(RADIANS($1)) * SIN(RADIANS(c.latitude))
+	))`
+
+// appendScreeningConds adds format, audio & subtitle conditions on schedule s,
+// each matched whole and case insensitively
+func appendScreeningConds(conds []string, args []any, f models.ScreeningFilter) ([]string, []any) {
+	if f.Format != "" {
+		args = append(args, f.Format)
+		conds = append(conds, fmt.Sprintf("LOWER(s.format) = LOWER($%d)", len(args)))
+	}
+	if f.Audio != "" {
+		args = append(args, f.Audio)
+		conds = append(conds, fmt.Sprintf("LOWER(s.audio_language) = LOWER($%d)", len(args)))
+	}
+	if f.Subtitle != "" {
+		args = append(args, f.Subtitle)
+		conds = append(conds, fmt.Sprintf("LOWER(s.subtitle_language) = LOWER($%d)", len(args)))
+	}
+	return conds, args
+}
+
+type cachedSchedules struct {
+	Schedules []models.CinemaSchedule `json:"schedules"`
+	Total     int                     `json:"total"`
+}
+
+// GetSchedules lists the upcoming schedules matching filter, movie titles in lang
+func (c *CinemaRepository) GetSchedules(ctx context.Context, filter models.ScheduleFilter, limit, offset int, lang string) ([]models.CinemaSchedule, int, error) {
+	redisKey := fmt.Sprintf(
+		"archie:schedules_%s_m%d_d%s_c%s_ci%d_f%s_a%s_s%s_l%d_o%d",
+		lang, filter.MovieID, filter.Date, strings.ToLower(filter.City), filter.CinemaID,
+		strings.ToLower(filter.Format), strings.ToLower(filter.Audio), strings.ToLower(filter.Subtitle),
+		limit, offset,
+	)
+	var cached cachedSchedules
+
+	isExist, err := utils.CacheGet(c.rdb, ctx, redisKey, &cached)
+	if err != nil {
+		utils.PrintError("redis> REDIS ERROR", 20, err)
+	}
+	if isExist {
+		return cached.Schedules, cached.Total, nil
+	}
+
+	conds := []string{liveScheduleCond, upcomingScheduleCond}
+	args := []any{}
+	if filter.MovieID != 0 {
+		args = append(args, filter.MovieID)
+		conds = append(conds, fmt.Sprintf("s.movie_id = $%d", len(args)))
+	}
+	if filter.Date != "" {
+		args = append(args, filter.Date)
+		conds = append(conds, fmt.Sprintf("s.show_date = $%d", len(args)))
+	}
+	if filter.City != "" {
+		args = append(args, escapeLike(filter.City))
+		conds = append(conds, fmt.Sprintf("l.show_location ILIKE $%d", len(args)))
+	}
+	if filter.CinemaID != 0 {
+		args = append(args, filter.CinemaID)
+		conds = append(conds, fmt.Sprintf("s.cinema_id = $%d", len(args)))
+	}
+	conds, args = appendScreeningConds(conds, args, filter.ScreeningFilter)
+
+	baseSQL := `
 		FROM 
 			schedule AS s
 		JOIN
@@ -28,7 +122,88 @@ func (c *CinemaRepository) GetSchedule(ctx context.Context) ([]models.CinemaSche
 		JOIN
 			lokasi_tayang AS l ON s.location_id = l.id
 		JOIN
-			cinema_tayang AS c ON s.cinema_id = c.id;
+			cinema_tayang AS c ON s.cinema_id = c.id
+		JOIN
+			screen_formats AS f ON f.code = s.format
+		WHERE
+	` + strings.Join(conds, " AND ")
+
+	var total int
+	if err := c.dbpool.QueryRow(ctx, "SELECT COUNT(*) "+baseSQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql := `
+		SELECT 
+			s.id, m.id, m.title, m.poster_path, s.show_date, t.show_time, l.show_location, c.id, c.cinema_name, c.cinema_img,
+			s.format, s.audio_language, s.subtitle_language, ` + schedulePriceExpr + `,
+			` + scheduleStartsAtExpr + `, l.timezone
+	` + baseSQL + fmt.Sprintf(`
+		ORDER BY
+			`+scheduleStartsAtExpr+` ASC, s.id ASC
+		LIMIT $%d OFFSET $%d
+	`, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := c.dbpool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	schedules := []models.CinemaSchedule{}
+	for rows.Next() {
+		var schedule models.CinemaSchedule
+		var startsAt time.Time
+		var timezone string
+
+		if err := rows.Scan(
+			&schedule.ID,
+			&schedule.MovieID,
+			&schedule.Title,
+			&schedule.PosterPath,
+			&schedule.Date,
+			&schedule.Time,
+			&schedule.Location,
+			&schedule.CinemaID,
+			&schedule.CinemaName,
+			&schedule.CinemaImg,
+			&schedule.Format,
+			&schedule.AudioLanguage,
+			&schedule.SubtitleLanguage,
+			&schedule.Price,
+			&startsAt,
+			&timezone,
+		); err != nil {
+			return nil, 0, err
+		}
+		schedule.LocalTime = models.NewLocalTime(startsAt, timezone)
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := localizeMovies(ctx, c.dbpool, lang, schedules, func(s *models.CinemaSchedule) localized {
+		return localized{id: s.MovieID, title: &s.Title}
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	expiration := 10 * time.Minute
+	if err := utils.CacheSet(c.rdb, ctx, redisKey, cachedSchedules{schedules, total}, expiration); err != nil {
+		utils.PrintError(
+			fmt.Sprintf("redis> UNABLE TO SET %s", redisKey), 20, err,
+		)
+	}
+
+	return schedules, total, nil
+}
+
+func (c *CinemaRepository) GetScreenFormats(ctx context.Context) ([]models.ScreenFormat, error) {
+	sql := `
+		SELECT code, surcharge
+		FROM screen_formats
+		ORDER BY surcharge ASC
 	`
 	rows, err := c.dbpool.Query(ctx, sql)
 	if err != nil {
@@ -36,56 +211,142 @@ func (c *CinemaRepository) GetSchedule(ctx context.Context) ([]models.CinemaSche
 	}
 	defer rows.Close()
 
-	var schedules []models.CinemaSchedule
+	var formats []models.ScreenFormat
 	for rows.Next() {
-		var schedule models.CinemaSchedule
+		var format models.ScreenFormat
+		if err := rows.Scan(&format.Code, &format.Surcharge); err != nil {
+			return nil, err
+		}
+
+		formats = append(formats, format)
+	}
+
+	return formats, nil
+}
+
+func (c *CinemaRepository) GetCinemas(ctx context.Context) ([]models.Cinema, error) {
+	sql := `
+		SELECT
+			c.id, c.cinema_name, c.cinema_img, c.address, c.latitude, c.longitude,
+			COALESCE(ARRAY_AGG(DISTINCT l.show_location) FILTER (WHERE l.id IS NOT NULL), '{}')
+		FROM
+			cinema_tayang c
+		LEFT JOIN
+			schedule s ON s.cinema_id = c.id
+		LEFT JOIN
+			lokasi_tayang l ON l.id = s.location_id
+		GROUP BY
+			c.id
+		ORDER BY
+			c.id ASC
+	`
+	rows, err := c.dbpool.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		if err := rows.Scan(&schedule.ID, &schedule.Title, &schedule.Date, &schedule.Time, &schedule.Location, &schedule.CinemaName); err != nil {
+	var cinemas []models.Cinema
+	for rows.Next() {
+		var cinema models.Cinema
+		if err := rows.Scan(
+			&cinema.ID,
+			&cinema.Name,
+			&cinema.Img,
+			&cinema.Address,
+			&cinema.Latitude,
+			&cinema.Longitude,
+			&cinema.Locations,
+		); err != nil {
 			return nil, err
 		}
-		schedules = append(schedules, schedule)
+
+		cinemas = append(cinemas, cinema)
+	}
+
+	return cinemas, nil
+}
+
+func (c *CinemaRepository) GetCinemaDetail(ctx context.Context, cinemaId int) (models.Cinema, error) {
+	sql := `
+		SELECT
+			c.id, c.cinema_name, c.cinema_img, c.address, c.latitude, c.longitude,
+			COALESCE(ARRAY_AGG(DISTINCT l.show_location) FILTER (WHERE l.id IS NOT NULL), '{}')
+		FROM
+			cinema_tayang c
+		LEFT JOIN
+			schedule s ON s.cinema_id = c.id
+		LEFT JOIN
+			lokasi_tayang l ON l.id = s.location_id
+		WHERE
+			c.id = $1
+		GROUP BY
+			c.id
+	`
+
+	var cinema models.Cinema
+	if err := c.dbpool.QueryRow(ctx, sql, cinemaId).Scan(
+		&cinema.ID,
+		&cinema.Name,
+		&cinema.Img,
+		&cinema.Address,
+		&cinema.Latitude,
+		&cinema.Longitude,
+		&cinema.Locations,
+	); err != nil {
+		return models.Cinema{}, err
 	}
 
-	return schedules, nil
+	return cinema, nil
 }
 
 func (c *CinemaRepository) GetCinemaNameAndTime(ctx context.Context, scheduleId int) (models.CinemaAndTime, error) {
 	sql := `
-		SELECT ct.cinema_name, jt.show_time, ct.cinema_img
+		SELECT ct.cinema_name, t.show_time, ct.cinema_img, ` + scheduleStartsAtExpr + `, l.timezone
 		FROM schedule s
+		JOIN movies m ON m.id = s.movie_id
 		JOIN cinema_tayang ct ON ct.id = s.cinema_id 
-		JOIN jam_tayang jt ON jt.id = s.time_id
-		WHERE s.id = $1
+		JOIN jam_tayang t ON t.id = s.time_id
+		JOIN lokasi_tayang l ON l.id = s.location_id
+		WHERE s.id = $1 AND ` + liveScheduleCond + `
 	`
 
 	var result models.CinemaAndTime
+	var startsAt time.Time
+	var timezone string
 	if err := c.dbpool.QueryRow(ctx, sql, scheduleId).Scan(
 		&result.CinemaName,
 		&result.Time,
 		&result.CinemaImg,
+		&startsAt,
+		&timezone,
 	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.CinemaAndTime{}, ErrScheduleNotFound
+		}
 		return models.CinemaAndTime{}, err
 	}
+	result.LocalTime = models.NewLocalTime(startsAt, timezone)
 
 	return result, nil
 }
 
 func (c *CinemaRepository) GetAvailSeats(ctx context.Context, scheduleId int) ([]models.Seat, error) {
-	var scheduleExists bool
+	// cancelled showtimes and withdrawn movies are not booked anymore
+	var isLive bool
 	checkScheduleSql := `
 		SELECT EXISTS (
 			SELECT 1
-			FROM "orders"
-			WHERE schedule_id = $1
+			FROM schedule s
+			JOIN movies m ON m.id = s.movie_id
+			WHERE s.id = $1 AND ` + liveScheduleCond + `
 		)
 	`
-	if err := c.dbpool.QueryRow(ctx, checkScheduleSql, scheduleId).Scan(&scheduleExists); err != nil {
+	if err := c.dbpool.QueryRow(ctx, checkScheduleSql, scheduleId).Scan(&isLive); err != nil {
 		return nil, err
 	}
-
-	// if schedule_id not found
-	if !scheduleExists {
-		return []models.Seat{}, nil
+	if !isLive {
+		return nil, ErrScheduleNotFound
 	}
 
 	sql := `
@@ -124,3 +385,159 @@ func (c *CinemaRepository) GetAvailSeats(ctx context.Context, scheduleId int) ([
 	}
 	return seats, nil
 }
+
+func (c *CinemaRepository) GetNearbyCinemas(ctx context.Context, lat, lng, radiusKm float64, movieId int) ([]models.NearbyCinema, error) {
+	sql := `
+		SELECT
+			id, cinema_name, cinema_img, address, latitude, longitude, distance
+		FROM (
+			SELECT
+				c.id, c.cinema_name, c.cinema_img, c.address, c.latitude, c.longitude,
+				` + haversineExpr + ` AS distance
+			FROM
+				cinema_tayang c
+			WHERE
+				c.latitude IS NOT NULL AND c.longitude IS NOT NULL
+		) nearby
+		WHERE
+			distance <= $3
+		ORDER BY
+			distance ASC
+	`
+	rows, err := c.dbpool.Query(ctx, sql, lat, lng, radiusKm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cinemas := []models.NearbyCinema{}
+	cinemaIds := []int{}
+	for rows.Next() {
+		var cinema models.NearbyCinema
+		if err := rows.Scan(
+			&cinema.ID,
+			&cinema.Name,
+			&cinema.Img,
+			&cinema.Address,
+			&cinema.Latitude,
+			&cinema.Longitude,
+			&cinema.DistanceKm,
+		); err != nil {
+			return nil, err
+		}
+
+		cinemas = append(cinemas, cinema)
+		cinemaIds = append(cinemaIds, int(cinema.ID))
+	}
+	rows.Close()
+
+	if movieId == 0 || len(cinemas) == 0 {
+		return cinemas, nil
+	}
+
+	showtimes, err := c.getTodayShowtimes(ctx, movieId, cinemaIds)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cinemas {
+		cinemas[i].Showtimes = showtimes[cinemas[i].ID]
+	}
+
+	return cinemas, nil
+}
+
+// getTodayShowtimes returns today's remaining showtimes of a movie keyed by cinema ID
+func (c *CinemaRepository) getTodayShowtimes(ctx context.Context, movieId int, cinemaIds []int) (map[uint16][]models.Showtime, error) {
+	sql := `
+		SELECT
+			s.cinema_id, s.id, t.show_time, s.format, s.audio_language, s.subtitle_language,
+			` + schedulePriceExpr + `, ` + scheduleStartsAtExpr + `, l.timezone,
+			(SELECT COUNT(*) FROM seats) - (
+				SELECT COUNT(*)
+				FROM orders_seats os
+				JOIN orders o ON o.id = os.order_id
+				WHERE o.schedule_id = s.id
+			) AS remaining_seats
+		FROM
+			schedule s
+		JOIN
+			movies m ON m.id = s.movie_id
+		JOIN
+			jam_tayang t ON t.id = s.time_id
+		JOIN
+			lokasi_tayang l ON l.id = s.location_id
+		JOIN
+			screen_formats f ON f.code = s.format
+		WHERE
+			s.movie_id = $1
+		AND
+			s.cinema_id = ANY($2)
+		AND
+			` + liveScheduleCond + `
+		AND
+			` + todayRemainingCond + `
+		ORDER BY
+			` + scheduleStartsAtExpr + ` ASC, s.id ASC
+	`
+	rows, err := c.dbpool.Query(ctx, sql, movieId, cinemaIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	showtimes := make(map[uint16][]models.Showtime)
+	for rows.Next() {
+		var (
+			cinemaId uint16
+			show     models.Showtime
+			startsAt time.Time
+			timezone string
+		)
+		if err := rows.Scan(
+			&cinemaId,
+			&show.ScheduleID,
+			&show.Time,
+			&show.Format,
+			&show.AudioLanguage,
+			&show.SubtitleLanguage,
+			&show.Price,
+			&startsAt,
+			&timezone,
+			&show.RemainingSeats,
+		); err != nil {
+			return nil, err
+		}
+		show.LocalTime = models.NewLocalTime(startsAt, timezone)
+
+		showtimes[cinemaId] = append(showtimes[cinemaId], show)
+	}
+
+	return showtimes, nil
+}
+
+func (c *CinemaRepository) UpdateCinema(ctx context.Context, body models.UpdateCinemaBody, cinemaId int) (pgconn.CommandTag, error) {
+	sql, args, err := updateSQL("cinema_tayang", body, cinemaId)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return c.dbpool.Exec(ctx, sql, args...)
+}
+
+func (c *CinemaRepository) UpdateLocationTimezone(ctx context.Context, locationId int, timezone string) (pgconn.CommandTag, error) {
+	sql := `
+		UPDATE lokasi_tayang
+		SET timezone = $1
+		WHERE id = $2
+	`
+	ctag, err := c.dbpool.Exec(ctx, sql, timezone, locationId)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	if err := utils.InvalidateCachePattern(c.rdb, ctx, "archie:schedules_*"); err != nil {
+		utils.PrintError("redis> UNABLE INVALIDATE SCHEDULES", 20, err)
+	}
+
+	return ctag, nil
+}
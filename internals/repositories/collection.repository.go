@@ -0,0 +1,240 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+var (
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrMovieInCollection   = errors.New("movie already belongs to another collection")
+	ErrDuplicateCollection = errors.New("a movie is listed twice")
+)
+
+func (m *MovieRepository) GetCollections(ctx context.Context, limit, offset int) ([]models.CollectionSummary, int, error) {
+	var total int
+	if err := m.dbpool.QueryRow(ctx, "SELECT COUNT(*) FROM collections").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql := `
+		SELECT c.id, c.name, (SELECT COUNT(*) FROM collection_movies cm WHERE cm.collection_id = c.id)
+		FROM collections c
+		ORDER BY LOWER(c.name) ASC, c.id ASC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := m.dbpool.Query(ctx, sql, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	collections := []models.CollectionSummary{}
+	for rows.Next() {
+		var collection models.CollectionSummary
+		if err := rows.Scan(&collection.ID, &collection.Name, &collection.MovieCount); err != nil {
+			return nil, 0, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, total, rows.Err()
+}
+
+func (m *MovieRepository) CreateCollection(ctx context.Context, body models.CollectionBody) (int, error) {
+	var id int
+	if err := m.dbpool.QueryRow(ctx,
+		"INSERT INTO collections (name, overview) VALUES ($1, $2) RETURNING id", body.Name, body.Overview,
+	).Scan(&id); err != nil {
+		if utils.IsPgError(err, utils.PgUniqueViolation) {
+			return 0, ErrDuplicateName
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateCollection renames a collection, its overview is kept when not given
+func (m *MovieRepository) UpdateCollection(ctx context.Context, id int, body models.CollectionBody) error {
+	sql := `
+		UPDATE collections
+		SET name = $1, overview = COALESCE($2, overview), updated_at = now()
+		WHERE id = $3
+	`
+	ctag, err := m.dbpool.Exec(ctx, sql, body.Name, body.Overview, id)
+	if err != nil {
+		if utils.IsPgError(err, utils.PgUniqueViolation) {
+			return ErrDuplicateName
+		}
+		return err
+	}
+	if ctag.RowsAffected() == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// DeleteCollection removes a collection, its movies are kept
+func (m *MovieRepository) DeleteCollection(ctx context.Context, id int) error {
+	ctag, err := m.dbpool.Exec(ctx, "DELETE FROM collections WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if ctag.RowsAffected() == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// SetCollectionMovies replaces the movies of a collection with ids, in
+// series order. a movie of another collection must leave it first
+func (m *MovieRepository) SetCollectionMovies(ctx context.Context, id int, ids []int) error {
+	// a repeat would hit the unique movie index like a movie of another
+	// collection does
+	if len(slices.Compact(slices.Sorted(slices.Values(ids)))) != len(ids) {
+		return ErrDuplicateCollection
+	}
+
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.QueryRow(ctx, "SELECT id FROM collections WHERE id = $1 FOR UPDATE", id).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCollectionNotFound
+		}
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM collection_movies WHERE collection_id = $1", id); err != nil {
+		return err
+	}
+
+	sql := `
+		INSERT INTO collection_movies (collection_id, movie_id, position)
+		SELECT $1, m.id, o.ord - 1
+		FROM UNNEST($2::int[]) WITH ORDINALITY AS o(id, ord)
+		JOIN movies m ON m.id = o.id AND m.deleted_at IS NULL
+	`
+	ctag, err := tx.Exec(ctx, sql, id, ids)
+	if err != nil {
+		if utils.IsPgError(err, utils.PgUniqueViolation) {
+			return ErrMovieInCollection
+		}
+		return err
+	}
+	if ctag.RowsAffected() != int64(len(ids)) {
+		return fmt.Errorf("%w: every id must be a movie", ErrMovieNotFound)
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE collections SET updated_at = now() WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetCollection returns a collection with its live movies in series order
+func (m *MovieRepository) GetCollection(ctx context.Context, id int, lang string) (models.Collection, error) {
+	var collection models.Collection
+	if err := m.dbpool.QueryRow(ctx,
+		"SELECT id, name, overview FROM collections WHERE id = $1", id,
+	).Scan(&collection.ID, &collection.Name, &collection.Overview); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Collection{}, ErrCollectionNotFound
+		}
+		return models.Collection{}, err
+	}
+
+	movies, err := m.fetchCollectionMovies(ctx, id, 0, lang)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	collection.Movies = movies
+	return collection, nil
+}
+
+// fetchMovieCollection returns the collection of a movie with the other
+// movies of it, nil when the movie belongs to none
+func (m *MovieRepository) fetchMovieCollection(ctx context.Context, movieId int, lang string) (*models.MovieCollection, error) {
+	sql := `
+		SELECT c.id, c.name
+		FROM collection_movies cm
+		JOIN collections c ON c.id = cm.collection_id
+		WHERE cm.movie_id = $1
+	`
+	var collection models.MovieCollection
+	if err := m.dbpool.QueryRow(ctx, sql, movieId).Scan(&collection.ID, &collection.Name); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	siblings, err := m.fetchCollectionMovies(ctx, collection.ID, movieId, lang)
+	if err != nil {
+		return nil, err
+	}
+	collection.Siblings = siblings
+	return &collection, nil
+}
+
+// fetchCollectionMovies returns the live movies of a collection but
+// exceptId, each with its first upcoming showtime
+func (m *MovieRepository) fetchCollectionMovies(ctx context.Context, collectionId, exceptId int, lang string) ([]models.CollectionMovie, error) {
+	sql := `
+		SELECT
+			m.id, m.title, m.poster_path, m.release_date, m.age_rating, cm.position,
+			(
+				SELECT MIN(` + scheduleStartsAtExpr + `)
+				FROM schedule s
+				JOIN jam_tayang t ON t.id = s.time_id
+				JOIN lokasi_tayang l ON l.id = s.location_id
+				WHERE s.movie_id = m.id AND ` + liveScheduleCond + ` AND ` + upcomingScheduleCond + `
+			)
+		FROM
+			collection_movies cm
+		JOIN
+			movies m ON m.id = cm.movie_id
+		WHERE
+			cm.collection_id = $1 AND m.id <> $2 AND m.deleted_at IS NULL
+		ORDER BY
+			cm.position ASC, m.release_date ASC, m.id ASC
+	`
+	rows, err := m.dbpool.Query(ctx, sql, collectionId, exceptId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movies := []models.CollectionMovie{}
+	for rows.Next() {
+		var movie models.CollectionMovie
+		if err := rows.Scan(
+			&movie.ID,
+			&movie.Title,
+			&movie.PosterPath,
+			&movie.ReleaseDate,
+			&movie.AgeRating,
+			&movie.Position,
+			&movie.NextShowtime,
+		); err != nil {
+			return nil, err
+		}
+		movie.NowShowing = movie.NextShowtime != nil
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := localizeMovies(ctx, m.dbpool, lang, movies, collectionMovieFields); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSetCollectionMoviesRejectsRepeats(t *testing.T) {
+	// the repeat is caught before the database is reached
+	err := (&MovieRepository{}).SetCollectionMovies(context.Background(), 1, []int{4, 7, 4})
+	if !errors.Is(err, ErrDuplicateCollection) {
+		t.Errorf("SetCollectionMovies = %v, want ErrDuplicateCollection", err)
+	}
+}
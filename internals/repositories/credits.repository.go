@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/metgag/koda-weekly10/internals/models"
+)
+
+var ErrInvalidCredits = errors.New("invalid credits")
+
+var crewJobs = []string{models.CrewDirector, models.CrewWriter, models.CrewProducer, models.CrewComposer}
+
+// ParseCredits reads the credits field of the movie forms, an empty field
+// changes no credits
+func ParseCredits(raw string) (models.CreditsBody, error) {
+	var credits models.CreditsBody
+	if strings.TrimSpace(raw) == "" {
+		return credits, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &credits); err != nil {
+		return models.CreditsBody{}, fmt.Errorf("%w: %s", ErrInvalidCredits, err.Error())
+	}
+
+	// a person is credited once in the cast and once per job
+	seen := map[string]bool{}
+	for i, c := range credits.Cast {
+		key, err := creditKey(c.PersonID, c.Name, "cast")
+		if err != nil {
+			return models.CreditsBody{}, fmt.Errorf("%w: cast %d %s", ErrInvalidCredits, i+1, err.Error())
+		}
+		if seen[key] {
+			return models.CreditsBody{}, fmt.Errorf("%w: cast %d is credited twice", ErrInvalidCredits, i+1)
+		}
+		seen[key] = true
+	}
+	for i, c := range credits.Crew {
+		if !slices.Contains(crewJobs, c.Job) {
+			return models.CreditsBody{}, fmt.Errorf("%w: crew %d job must be one of %s", ErrInvalidCredits, i+1, strings.Join(crewJobs, ", "))
+		}
+		key, err := creditKey(c.PersonID, c.Name, c.Job)
+		if err != nil {
+			return models.CreditsBody{}, fmt.Errorf("%w: crew %d %s", ErrInvalidCredits, i+1, err.Error())
+		}
+		if seen[key] {
+			return models.CreditsBody{}, fmt.Errorf("%w: crew %d is credited twice as %s", ErrInvalidCredits, i+1, c.Job)
+		}
+		seen[key] = true
+	}
+	return credits, nil
+}
+
+func creditKey(personId int, name, role string) (string, error) {
+	switch {
+	case personId > 0:
+		return fmt.Sprintf("%s:%d", role, personId), nil
+	case personId == 0 && strings.TrimSpace(name) != "":
+		return fmt.Sprintf("%s:%s", role, strings.ToLower(strings.TrimSpace(name))), nil
+	default:
+		return "", errors.New("needs a person_id or a name")
+	}
+}
+
+// creditsDirector returns the director of the first crew director, ok is
+// false when the crew lists none
+func (m *MovieRepository) creditsDirector(tx pgx.Tx, ctx context.Context, credits models.CreditsBody) (uint16, bool, error) {
+	for _, c := range credits.Crew {
+		if c.Job != models.CrewDirector {
+			continue
+		}
+		personId, err := m.resolvePerson(tx, ctx, c.PersonID, c.Name)
+		if err != nil {
+			return 0, false, err
+		}
+		directorId, err := m.directorForPerson(tx, ctx, personId)
+		return directorId, err == nil, err
+	}
+	return 0, false, nil
+}
+
+// resolvePerson returns personId when it exists, or the person named name,
+// added when there is none yet
+func (m *MovieRepository) resolvePerson(tx pgx.Tx, ctx context.Context, personId int, name string) (int, error) {
+	if personId > 0 {
+		if err := tx.QueryRow(ctx, "SELECT id FROM casts WHERE id = $1", personId).Scan(&personId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, fmt.Errorf("%w: person %d not found", ErrInvalidCredits, personId)
+			}
+			return 0, err
+		}
+		return personId, nil
+	}
+
+	sql := `
+		WITH ins AS (
+			INSERT INTO casts (name)
+			VALUES ($1)
+			ON CONFLICT (name) DO NOTHING
+			RETURNING id
+		)
+		SELECT id FROM ins
+		UNION
+		SELECT id FROM casts WHERE name = $1
+		LIMIT 1
+	`
+	if err := tx.QueryRow(ctx, sql, strings.TrimSpace(name)).Scan(&personId); err != nil {
+		return 0, err
+	}
+	return personId, nil
+}
+
+// directorForPerson returns the director linked to a person, added when the
+// person has not directed yet
+func (m *MovieRepository) directorForPerson(tx pgx.Tx, ctx context.Context, personId int) (uint16, error) {
+	var directorId uint16
+	err := tx.QueryRow(ctx,
+		"SELECT id FROM directors WHERE person_id = $1 ORDER BY id LIMIT 1", personId,
+	).Scan(&directorId)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return directorId, err
+	}
+
+	sql := `
+		INSERT INTO directors (name, person_id)
+		SELECT name, id FROM casts WHERE id = $1
+		ON CONFLICT (name) DO UPDATE SET person_id = COALESCE(directors.person_id, EXCLUDED.person_id)
+		RETURNING id
+	`
+	err = tx.QueryRow(ctx, sql, personId).Scan(&directorId)
+	return directorId, err
+}
+
+// replaceMovieCredits replaces the cast and crew of a movie with the lists
+// credits carries. the first crew director becomes the movie's director, a
+// crew without one keeps the current director
+func (m *MovieRepository) replaceMovieCredits(tx pgx.Tx, ctx context.Context, movieID uint32, credits models.CreditsBody) error {
+	if credits.Cast != nil {
+		if _, err := tx.Exec(ctx, "DELETE FROM movies_casts WHERE movie_id = $1", movieID); err != nil {
+			return err
+		}
+		// ParseCredits only catches repeats given alike, a person may be given
+		// once by id and once by name
+		credited := map[int]bool{}
+		for i, c := range credits.Cast {
+			personId, err := m.resolvePerson(tx, ctx, c.PersonID, c.Name)
+			if err != nil {
+				return err
+			}
+			if credited[personId] {
+				return fmt.Errorf("%w: cast %d is credited twice", ErrInvalidCredits, i+1)
+			}
+			credited[personId] = true
+			order := i + 1
+			if c.Order != nil {
+				order = *c.Order
+			}
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO movies_casts (movie_id, cast_id, character_name, billing_order)
+				VALUES ($1, $2, NULLIF($3, ''), $4)
+			`, movieID, personId, strings.TrimSpace(c.Character), order); err != nil {
+				return err
+			}
+		}
+	}
+
+	if credits.Crew == nil {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM movie_crew WHERE movie_id = $1", movieID); err != nil {
+		return err
+	}
+	positions := map[string]int{}
+	credited := map[string]bool{}
+	for i, c := range credits.Crew {
+		personId, err := m.resolvePerson(tx, ctx, c.PersonID, c.Name)
+		if err != nil {
+			return err
+		}
+		key, _ := creditKey(personId, "", c.Job)
+		if credited[key] {
+			return fmt.Errorf("%w: crew %d is credited twice as %s", ErrInvalidCredits, i+1, c.Job)
+		}
+		credited[key] = true
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO movie_crew (movie_id, person_id, job, position)
+			VALUES ($1, $2, $3, $4)
+		`, movieID, personId, c.Job, positions[c.Job]); err != nil {
+			return err
+		}
+		positions[c.Job]++
+	}
+
+	if positions[models.CrewDirector] == 0 {
+		return m.syncDirectorCrew(tx, ctx, movieID)
+	}
+	directorId, _, err := m.creditsDirector(tx, ctx, credits)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, "UPDATE movies SET director_id = $1 WHERE id = $2", directorId, movieID)
+	return err
+}
+
+// syncDirectorCrew makes the movie's director its only crew director, for
+// the director_name field which knows a single director
+func (m *MovieRepository) syncDirectorCrew(tx pgx.Tx, ctx context.Context, movieID uint32) error {
+	if _, err := tx.Exec(ctx,
+		"DELETE FROM movie_crew WHERE movie_id = $1 AND job = 'director'", movieID,
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO movie_crew (movie_id, person_id, job)
+		SELECT m.id, d.person_id, 'director'
+		FROM movies m
+		JOIN directors d ON d.id = m.director_id
+		WHERE m.id = $1 AND d.person_id IS NOT NULL
+	`, movieID)
+	return err
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCredits(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		ok   bool
+	}{
+		{"empty", "  ", true},
+		{"not json", "[1,2]", false},
+		{"cast and crew", `{"cast":[{"person_id":31,"character":"Forrest"},{"name":"Sally Field"}],"crew":[{"person_id":40,"job":"director"}]}`, true},
+		{"nobody", `{"cast":[{"character":"Forrest"}]}`, false},
+		{"cast twice by id", `{"cast":[{"person_id":31},{"person_id":31}]}`, false},
+		{"cast twice by name", `{"cast":[{"name":"Sally Field"},{"name":" sally field "}]}`, false},
+		{"unknown job", `{"crew":[{"person_id":40,"job":"gaffer"}]}`, false},
+		{"crew job twice", `{"crew":[{"person_id":40,"job":"writer"},{"person_id":40,"job":"writer"}]}`, false},
+		{"two jobs", `{"crew":[{"person_id":40,"job":"director"},{"person_id":40,"job":"writer"}]}`, true},
+		{"cast and crew alike", `{"cast":[{"person_id":40}],"crew":[{"person_id":40,"job":"director"}]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCredits(tt.raw)
+			if (err == nil) != tt.ok {
+				t.Fatalf("ParseCredits = %v, want ok %v", err, tt.ok)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCredits) {
+				t.Errorf("error %v is not ErrInvalidCredits", err)
+			}
+		})
+	}
+}
+
+func TestParseCreditsKeepsOrder(t *testing.T) {
+	credits, err := ParseCredits(`{"cast":[{"name":"B"},{"name":"A"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(credits.Cast) != 2 || credits.Cast[0].Name != "B" || credits.Cast[1].Name != "A" {
+		t.Errorf("cast = %+v, want B billed before A", credits.Cast)
+	}
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+var (
+	ErrGenreNotFound = errors.New("genre not found")
+	ErrGenreInUse    = errors.New("genre is still used by movies")
+)
+
+func (m *MovieRepository) CreateGenre(ctx context.Context, name string) (int, error) {
+	var id int
+	if err := m.dbpool.QueryRow(ctx,
+		"INSERT INTO genres (genre_name) VALUES ($1) RETURNING id", name,
+	).Scan(&id); err != nil {
+		if utils.IsPgError(err, utils.PgUniqueViolation) {
+			return 0, ErrDuplicateName
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// RenameGenre renames a genre, the seeded aliases like "sci-fi" keep
+// resolving to it
+func (m *MovieRepository) RenameGenre(ctx context.Context, id int, name string) error {
+	ctag, err := m.dbpool.Exec(ctx, "UPDATE genres SET genre_name = $1 WHERE id = $2", name, id)
+	if err != nil {
+		if utils.IsPgError(err, utils.PgUniqueViolation) {
+			return ErrDuplicateName
+		}
+		return err
+	}
+	if ctag.RowsAffected() == 0 {
+		return ErrGenreNotFound
+	}
+
+	m.bustMovieListCaches(ctx)
+	return nil
+}
+
+func (m *MovieRepository) DeleteGenre(ctx context.Context, id int) error {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var isUsed bool
+	if err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM movies_genres WHERE genre_id = g.id) FROM genres g WHERE g.id = $1", id,
+	).Scan(&isUsed); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrGenreNotFound
+		}
+		return err
+	}
+	if isUsed {
+		return ErrGenreInUse
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM genres WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import "testing"
+
+func TestGenreAlias(t *testing.T) {
+	tests := map[string]int{
+		"Action":          28,
+		"  sci-fi ":       878,
+		"Science Fiction": 878,
+		"TV":              10770,
+		"drama":           18,
+		"Anime":           0,
+		"":                0,
+	}
+	for name, want := range tests {
+		if got := genreAlias(name); got != want {
+			t.Errorf("genreAlias(%q) = %d, want %d", name, got, want)
+		}
+	}
+}
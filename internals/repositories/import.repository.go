@@ -0,0 +1,331 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/metgag/koda-weekly10/pkg"
+)
+
+var importImageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true}
+
+func isRemoteRef(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+}
+
+// validateImageRef accepts an http(s) URL or a bare filename uploaded to the
+// public dir, the file is not looked up when dir is empty
+func validateImageRef(field, ref, dir string) string {
+	if ref == "" {
+		return ""
+	}
+	if isRemoteRef(ref) {
+		if u, err := url.ParseRequestURI(ref); err != nil || u.Host == "" {
+			return fmt.Sprintf("%s is not a valid URL", field)
+		}
+		return ""
+	}
+	if ref != filepath.Base(ref) || !importImageExts[strings.ToLower(filepath.Ext(ref))] {
+		return fmt.Sprintf("%s must be an image URL or a .jpg, .jpeg, .png or .webp filename", field)
+	}
+	if dir != "" {
+		if _, err := os.Stat(filepath.Join("public", dir, ref)); err != nil {
+			return fmt.Sprintf("%s %s is not uploaded in public/%s", field, ref, dir)
+		}
+	}
+	return ""
+}
+
+// validateImportRow returns every problem of a row, seen holds the
+// title|release_date keys of the rows before it in the same file
+func (m *MovieRepository) validateImportRow(ctx context.Context, row models.ImportMovieRow, seen map[string]bool) []string {
+	if row.ParseError != "" {
+		return []string{row.ParseError}
+	}
+
+	errs := []string{}
+	if row.Title == "" {
+		errs = append(errs, "title is required")
+	}
+	if row.Director == "" {
+		errs = append(errs, "director is required")
+	}
+	if _, err := time.Parse("2006-01-02", row.ReleaseDate); err != nil {
+		errs = append(errs, "release_date must be in YYYY-MM-DD format")
+	}
+	if row.Runtime != "" {
+		if runtime, err := strconv.Atoi(row.Runtime); err != nil || runtime <= 0 || runtime > 65535 {
+			errs = append(errs, "runtime must be a positive number of minutes")
+		}
+	}
+	for genre := range strings.SplitSeq(row.Genres, ",") {
+		if genre = strings.TrimSpace(genre); genre == "" {
+			continue
+		}
+		if _, err := m.castGenre(ctx, genre); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for field, ref := range map[string]string{"poster_path": row.PosterPath, "backdrop_path": row.BackdropPath} {
+		if msg := validateImageRef(field, ref, MediaDirs[strings.TrimSuffix(field, "_path")]); msg != "" {
+			errs = append(errs, msg)
+		}
+	}
+
+	key := strings.ToLower(row.Title) + "|" + row.ReleaseDate
+	if row.Title != "" && seen[key] {
+		errs = append(errs, "duplicate of an earlier row")
+	}
+	seen[key] = true
+
+	return errs
+}
+
+// existingImportMovies returns the title|release_date keys already in the catalogue
+func (m *MovieRepository) existingImportMovies(ctx context.Context, rows []models.ImportMovieRow) (map[string]bool, error) {
+	titles := make([]string, 0, len(rows))
+	for _, row := range rows {
+		titles = append(titles, strings.ToLower(row.Title))
+	}
+
+	sqlRows, err := m.dbpool.Query(ctx, `
+		SELECT LOWER(title) || '|' || TO_CHAR(release_date, 'YYYY-MM-DD')
+		FROM movies
+		WHERE LOWER(title) = ANY($1) AND deleted_at IS NULL
+	`, titles)
+	if err != nil {
+		return nil, err
+	}
+	defer sqlRows.Close()
+
+	existing := map[string]bool{}
+	for sqlRows.Next() {
+		var key string
+		if err := sqlRows.Scan(&key); err != nil {
+			return nil, err
+		}
+		existing[key] = true
+	}
+	return existing, sqlRows.Err()
+}
+
+// insertImportedMovie inserts a validated row, casts are given apart since
+// provider names may hold commas
+func (m *MovieRepository) insertImportedMovie(tx pgx.Tx, ctx context.Context, row models.ImportMovieRow, casts []string) (uint32, error) {
+	directorId, err := m.insertDirectors(tx, ctx, row.Director)
+	if err != nil {
+		return 0, err
+	}
+	releaseDate, _ := time.Parse("2006-01-02", row.ReleaseDate)
+	runtime, _ := strconv.Atoi(row.Runtime)
+
+	nullable := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+
+	sql := `
+		INSERT INTO movies (title, overview, runtime, release_date, director_id, poster_path, backdrop_path)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7)
+		RETURNING id
+	`
+	var movieID uint32
+	if err := tx.QueryRow(ctx, sql,
+		row.Title, row.Overview, runtime, releaseDate, directorId,
+		nullable(row.PosterPath), nullable(row.BackdropPath),
+	).Scan(&movieID); err != nil {
+		return 0, err
+	}
+
+	if _, err := m.insertMovieGenres(tx, ctx, movieID, row.Genres); err != nil {
+		return 0, err
+	}
+	if err := m.insertMovieCasts(tx, ctx, movieID, casts); err != nil {
+		return 0, err
+	}
+	if err := m.syncDirectorCrew(tx, ctx, movieID); err != nil {
+		return 0, err
+	}
+	if err := m.refreshSearchDocument(tx, ctx, movieID); err != nil {
+		return 0, err
+	}
+
+	return movieID, nil
+}
+
+// ImportMovies validates every row and, unless dryRun, inserts the valid ones
+// in a single transaction. invalid rows are reported and skipped
+func (m *MovieRepository) ImportMovies(ctx context.Context, rows []models.ImportMovieRow, dryRun bool) (models.MovieImportResult, error) {
+	result := models.MovieImportResult{
+		DryRun:   dryRun,
+		Total:    len(rows),
+		Imported: []uint32{},
+		Errors:   []models.ImportRowError{},
+	}
+
+	existing, err := m.existingImportMovies(ctx, rows)
+	if err != nil {
+		return result, err
+	}
+
+	var valid []models.ImportMovieRow
+	seen := map[string]bool{}
+	for _, row := range rows {
+		errs := m.validateImportRow(ctx, row, seen)
+		if existing[strings.ToLower(row.Title)+"|"+row.ReleaseDate] {
+			errs = append(errs, "movie already exists")
+		}
+		if len(errs) > 0 {
+			result.Errors = append(result.Errors, models.ImportRowError{
+				Line:   row.Line,
+				Title:  row.Title,
+				Errors: errs,
+			})
+			continue
+		}
+		valid = append(valid, row)
+	}
+	result.Valid = len(valid)
+
+	if dryRun || len(valid) == 0 {
+		return result, nil
+	}
+
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, row := range valid {
+		movieID, err := m.insertImportedMovie(tx, ctx, row, splitNames(row.Casts))
+		if err != nil {
+			return result, fmt.Errorf("line %d: %w", row.Line, err)
+		}
+		result.Imported = append(result.Imported, movieID)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return result, err
+	}
+
+	res, err := m.rdb.Del(ctx, upcomingsKeys()...).Result()
+	if err != nil {
+		log.Println(err)
+	}
+	log.Printf("Number of keys deleted: %d", res)
+	if err := utils.InvalidateCachePattern(m.rdb, ctx, "archie:schedules_*"); err != nil {
+		log.Println(err)
+	}
+	for _, movieID := range result.Imported {
+		if err := m.syncMovieSuggestions(ctx, int(movieID), nil); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return result, nil
+}
+
+var (
+	ErrMovieExists        = errors.New("movie already imported")
+	ErrIncompleteMetadata = errors.New("provider metadata is incomplete")
+)
+
+// CreateMovieFromMetadata inserts a movie fetched from a metadata provider and
+// remembers its external id so it can be re-synced later. genres unknown to
+// the catalogue are skipped
+func (m *MovieRepository) CreateMovieFromMetadata(ctx context.Context, provider string, meta pkg.MovieMetadata) (uint32, error) {
+	var existingID uint32
+	err := m.dbpool.QueryRow(ctx, `
+		SELECT id FROM movies
+		WHERE external_provider = $1 AND external_id = $2 AND deleted_at IS NULL
+	`, provider, meta.ExternalID).Scan(&existingID)
+	if err == nil {
+		return existingID, ErrMovieExists
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	var missing []string
+	if meta.Title == "" {
+		missing = append(missing, "title")
+	}
+	if meta.Director == "" {
+		missing = append(missing, "director")
+	}
+	if _, err := time.Parse("2006-01-02", meta.ReleaseDate); err != nil {
+		missing = append(missing, "release_date")
+	}
+	if len(missing) > 0 {
+		return 0, fmt.Errorf("%w: missing %s", ErrIncompleteMetadata, strings.Join(missing, ", "))
+	}
+
+	row := models.ImportMovieRow{
+		Title:        meta.Title,
+		Overview:     meta.Overview,
+		ReleaseDate:  meta.ReleaseDate,
+		Director:     meta.Director,
+		Genres:       strings.Join(meta.Genres, ","),
+		PosterPath:   meta.PosterURL,
+		BackdropPath: meta.BackdropURL,
+	}
+	if meta.Runtime > 0 {
+		row.Runtime = strconv.Itoa(int(meta.Runtime))
+	}
+
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	movieID, err := m.insertImportedMovie(tx, ctx, row, meta.Casts)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE movies
+		SET external_provider = $1, external_id = $2, popularity = $3, synced_at = now()
+		WHERE id = $4
+	`, provider, meta.ExternalID, meta.Popularity, movieID); err != nil {
+		// a concurrent import of the same movie won the race
+		if utils.IsPgError(err, utils.PgUniqueViolation) {
+			tx.Rollback(ctx)
+			if err := m.dbpool.QueryRow(ctx, `
+				SELECT id FROM movies
+				WHERE external_provider = $1 AND external_id = $2 AND deleted_at IS NULL
+			`, provider, meta.ExternalID).Scan(&existingID); err != nil {
+				return 0, err
+			}
+			return existingID, ErrMovieExists
+		}
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	res, err := m.rdb.Del(ctx, upcomingsKeys()...).Result()
+	if err != nil {
+		log.Println(err)
+	}
+	log.Printf("Number of keys deleted: %d", res)
+	if err := m.syncMovieSuggestions(ctx, int(movieID), nil); err != nil {
+		log.Println(err)
+	}
+
+	return movieID, nil
+}
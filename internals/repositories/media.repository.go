@@ -0,0 +1,348 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+var (
+	ErrMediaNotFound = errors.New("media not found")
+	ErrInvalidMedia  = errors.New("invalid media")
+)
+
+// MediaDirs are the public folders uploads of each kind are saved to, the
+// primary poster and backdrop are served from the same place as before
+var MediaDirs = map[string]string{
+	models.MediaPoster:   "poster",
+	models.MediaBackdrop: "backdrop",
+	models.MediaStill:    "media",
+}
+
+// MediaFilePath is where an uploaded image of kind is kept
+func MediaFilePath(kind, filename string) string {
+	return filepath.Join("public", MediaDirs[kind], filename)
+}
+
+// primary posters and backdrops are copied to the movie row, which every
+// listing still reads
+var mediaMovieColumns = map[string]string{
+	models.MediaPoster:   "poster_path",
+	models.MediaBackdrop: "backdrop_path",
+}
+
+// trailerProvider guesses the provider of a video URL when none is given
+func trailerProvider(u *url.URL) string {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch {
+	case host == "youtube.com" || host == "youtu.be" || strings.HasSuffix(host, ".youtube.com"):
+		return "youtube"
+	case host == "vimeo.com" || strings.HasSuffix(host, ".vimeo.com"):
+		return "vimeo"
+	}
+	return "file"
+}
+
+// ValidateMovieMedia checks an item before it is stored and fills the
+// provider of trailers. an upload is checked before it is written, other
+// filenames must name an image uploaded already
+func ValidateMovieMedia(item *models.MovieMedia, upload bool) error {
+	if item.URL == "" {
+		return fmt.Errorf("%w: a file or url is required", ErrInvalidMedia)
+	}
+	if item.Kind != models.MediaTrailer {
+		if item.Provider != nil {
+			return fmt.Errorf("%w: provider is only set on trailers", ErrInvalidMedia)
+		}
+		dir := MediaDirs[item.Kind]
+		if upload {
+			dir = ""
+		}
+		if msg := validateImageRef("url", item.URL, dir); msg != "" {
+			return fmt.Errorf("%w: %s", ErrInvalidMedia, msg)
+		}
+		return nil
+	}
+
+	u, err := url.ParseRequestURI(item.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("%w: trailer url must be an http(s) link", ErrInvalidMedia)
+	}
+	if item.Provider == nil {
+		provider := trailerProvider(u)
+		item.Provider = &provider
+	}
+	return nil
+}
+
+// bustMovieListCaches drops the cached lists that carry the poster
+func (m *MovieRepository) bustMovieListCaches(ctx context.Context) {
+	res, err := m.rdb.Del(ctx, upcomingsKeys()...).Result()
+	if err != nil {
+		log.Println(err)
+	}
+	log.Printf("Number of keys deleted: %d", res)
+	for _, pattern := range []string{"archie:movies_populars_*", "archie:schedules_now_showing_*", "archie:recommendations_*"} {
+		if err := utils.InvalidateCachePattern(m.rdb, ctx, pattern); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// setPrimaryMedia makes an item the primary of its kind and copies primary
+// images to the movie row
+func (m *MovieRepository) setPrimaryMedia(tx pgx.Tx, ctx context.Context, movieId, mediaId int, kind, mediaURL string) error {
+	// cleared first, the unique index is checked row by row
+	if _, err := tx.Exec(ctx,
+		"UPDATE movie_media SET is_primary = false WHERE movie_id = $1 AND kind = $2 AND is_primary", movieId, kind,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE movie_media SET is_primary = true WHERE id = $1", mediaId,
+	); err != nil {
+		return err
+	}
+
+	column, ok := mediaMovieColumns[kind]
+	if !ok {
+		return nil
+	}
+	_, err := tx.Exec(ctx, fmt.Sprintf("UPDATE movies SET %s = $1 WHERE id = $2", column), mediaURL, movieId)
+	return err
+}
+
+func (m *MovieRepository) AddMovieMedia(ctx context.Context, movieId int, item models.MovieMedia) (int, error) {
+	if err := ValidateMovieMedia(&item, false); err != nil {
+		return 0, err
+	}
+
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var isLive bool
+	if err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1 AND deleted_at IS NULL)", movieId,
+	).Scan(&isLive); err != nil {
+		return 0, err
+	}
+	if !isLive {
+		return 0, ErrMovieNotFound
+	}
+
+	// new items go last in their kind
+	sql := `
+		INSERT INTO movie_media (movie_id, kind, url, provider, language, caption, position)
+		SELECT $1, $2, $3, $4, $5, $6, COALESCE(MAX(position) + 1, 0)
+		FROM movie_media
+		WHERE movie_id = $1 AND kind = $2
+		RETURNING id
+	`
+	var mediaId int
+	if err := tx.QueryRow(ctx, sql,
+		movieId, item.Kind, item.URL, item.Provider, item.Language, item.Caption,
+	).Scan(&mediaId); err != nil {
+		return 0, err
+	}
+
+	if item.IsPrimary {
+		if err := m.setPrimaryMedia(tx, ctx, movieId, mediaId, item.Kind, item.URL); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	if item.IsPrimary && mediaMovieColumns[item.Kind] != "" {
+		m.bustMovieListCaches(ctx)
+	}
+
+	return mediaId, nil
+}
+
+func (m *MovieRepository) SetPrimaryMovieMedia(ctx context.Context, movieId, mediaId int) error {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var kind, mediaURL string
+	if err := tx.QueryRow(ctx,
+		"SELECT kind, url FROM movie_media WHERE id = $1 AND movie_id = $2", mediaId, movieId,
+	).Scan(&kind, &mediaURL); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMediaNotFound
+		}
+		return err
+	}
+	if err := m.setPrimaryMedia(tx, ctx, movieId, mediaId, kind, mediaURL); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	if mediaMovieColumns[kind] != "" {
+		m.bustMovieListCaches(ctx)
+	}
+	return nil
+}
+
+// ReorderMovieMedia sets the position of every listed item to its index in
+// ids, items of other kinds keep their relative order
+func (m *MovieRepository) ReorderMovieMedia(ctx context.Context, movieId int, ids []int) error {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+		UPDATE
+			movie_media mm
+		SET
+			position = o.ord - 1
+		FROM
+			UNNEST($2::int[]) WITH ORDINALITY AS o(id, ord)
+		WHERE
+			mm.id = o.id AND mm.movie_id = $1
+	`
+	ctag, err := tx.Exec(ctx, sql, movieId, ids)
+	if err != nil {
+		return err
+	}
+	if ctag.RowsAffected() != int64(len(ids)) {
+		return fmt.Errorf("%w: every id must be an item of the movie", ErrMediaNotFound)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteMovieMedia removes an item, the next one of its kind becomes primary
+// when the primary is removed. orphan is the path of the uploaded image the
+// item held once nothing refers to it anymore, empty otherwise
+func (m *MovieRepository) DeleteMovieMedia(ctx context.Context, movieId, mediaId int) (orphan string, err error) {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		kind, mediaURL string
+		isPrimary      bool
+	)
+	if err := tx.QueryRow(ctx,
+		"DELETE FROM movie_media WHERE id = $1 AND movie_id = $2 RETURNING kind, url, is_primary", mediaId, movieId,
+	).Scan(&kind, &mediaURL, &isPrimary); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrMediaNotFound
+		}
+		return "", err
+	}
+
+	promoted := false
+	if isPrimary {
+		var (
+			nextId  int
+			nextURL string
+		)
+		err := tx.QueryRow(ctx, `
+			SELECT id, url FROM movie_media
+			WHERE movie_id = $1 AND kind = $2
+			ORDER BY position ASC, id ASC
+			LIMIT 1
+		`, movieId, kind).Scan(&nextId, &nextURL)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return "", err
+		}
+		if err == nil {
+			if err := m.setPrimaryMedia(tx, ctx, movieId, nextId, kind, nextURL); err != nil {
+				return "", err
+			}
+			promoted = true
+		}
+	}
+
+	if orphan, err = orphanedMediaFile(tx, ctx, kind, mediaURL); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+	if promoted && mediaMovieColumns[kind] != "" {
+		m.bustMovieListCaches(ctx)
+	}
+	return orphan, nil
+}
+
+// orphanedMediaFile is the path of the uploaded image of kind at mediaURL once
+// nothing shows it anymore, "" while it is in use. urls are not ours to
+// remove, and a movie row may still show the file
+func orphanedMediaFile(tx pgx.Tx, ctx context.Context, kind, mediaURL string) (string, error) {
+	if _, ok := MediaDirs[kind]; !ok || mediaURL == "" || isRemoteRef(mediaURL) {
+		return "", nil
+	}
+	var inUse bool
+	if err := tx.QueryRow(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM movie_media WHERE kind = $1 AND url = $2)
+			OR EXISTS (SELECT 1 FROM movies WHERE poster_path = $2 OR backdrop_path = $2)
+	`, kind, mediaURL).Scan(&inUse); err != nil {
+		return "", err
+	}
+	if inUse {
+		return "", nil
+	}
+	return MediaFilePath(kind, mediaURL), nil
+}
+
+func (m *MovieRepository) fetchMedia(ctx context.Context, movieId int) ([]models.MovieMedia, error) {
+	sql := `
+		SELECT id, kind, url, provider, language, caption, position, is_primary, created_at
+		FROM movie_media
+		WHERE movie_id = $1
+		ORDER BY
+			ARRAY_POSITION(ARRAY['poster', 'backdrop', 'still', 'trailer']::varchar[], kind),
+			is_primary DESC, position ASC, id ASC
+	`
+	rows, err := m.dbpool.Query(ctx, sql, movieId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	media := []models.MovieMedia{}
+	for rows.Next() {
+		var item models.MovieMedia
+		if err := rows.Scan(
+			&item.ID,
+			&item.Kind,
+			&item.URL,
+			&item.Provider,
+			&item.Language,
+			&item.Caption,
+			&item.Position,
+			&item.IsPrimary,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		media = append(media, item)
+	}
+	return media, rows.Err()
+}
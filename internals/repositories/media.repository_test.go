@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/metgag/koda-weekly10/internals/models"
+)
+
+// publicDir runs the test from a temp dir holding public/media/still.jpg
+func publicDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "public", "media"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "public", "media", "still.jpg"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+}
+
+func TestValidateImageRef(t *testing.T) {
+	publicDir(t)
+
+	tests := []struct {
+		name, ref, dir string
+		ok             bool
+	}{
+		{"empty", "", "media", true},
+		{"remote", "https://image.tmdb.org/t/p/w500/a.jpg", "media", true},
+		{"remote without host", "https://", "media", false},
+		{"uploaded", "still.jpg", "media", true},
+		{"not uploaded", "missing.jpg", "media", false},
+		{"not looked up", "missing.jpg", "", true},
+		{"path", "../still.jpg", "media", false},
+		{"not an image", "still.gif", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := validateImageRef("url", tt.ref, tt.dir)
+			if (msg == "") != tt.ok {
+				t.Errorf("validateImageRef(%q, %q) = %q, want ok %v", tt.ref, tt.dir, msg, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidateMovieMedia(t *testing.T) {
+	publicDir(t)
+	youtube := "youtube"
+
+	tests := []struct {
+		name   string
+		item   models.MovieMedia
+		upload bool
+		ok     bool
+	}{
+		{"no url", models.MovieMedia{Kind: models.MediaStill}, false, false},
+		{"uploaded still", models.MovieMedia{Kind: models.MediaStill, URL: "still.jpg"}, false, true},
+		{"unknown still", models.MovieMedia{Kind: models.MediaStill, URL: "other.jpg"}, false, false},
+		{"still being uploaded", models.MovieMedia{Kind: models.MediaStill, URL: "other.jpg"}, true, true},
+		{"still with provider", models.MovieMedia{Kind: models.MediaStill, URL: "still.jpg", Provider: &youtube}, false, false},
+		{"trailer", models.MovieMedia{Kind: models.MediaTrailer, URL: "https://youtu.be/s7EdQ4FqbhY"}, false, true},
+		{"trailer filename", models.MovieMedia{Kind: models.MediaTrailer, URL: "trailer.mp4"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMovieMedia(&tt.item, tt.upload)
+			if (err == nil) != tt.ok {
+				t.Errorf("ValidateMovieMedia = %v, want ok %v", err, tt.ok)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidMedia) {
+				t.Errorf("error %v is not ErrInvalidMedia", err)
+			}
+		})
+	}
+}
+
+func TestValidateMovieMediaTrailerProvider(t *testing.T) {
+	tests := map[string]string{
+		"https://www.youtube.com/watch?v=s7EdQ4FqbhY": "youtube",
+		"https://m.youtube.com/watch?v=s7EdQ4FqbhY":   "youtube",
+		"https://vimeo.com/76979871":                  "vimeo",
+		"https://cdn.example.com/trailer.mp4":         "file",
+	}
+	for url, want := range tests {
+		item := models.MovieMedia{Kind: models.MediaTrailer, URL: url}
+		if err := ValidateMovieMedia(&item, false); err != nil {
+			t.Fatalf("ValidateMovieMedia(%q) = %v", url, err)
+		}
+		if item.Provider == nil || *item.Provider != want {
+			t.Errorf("provider of %q = %v, want %q", url, item.Provider, want)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -28,21 +30,25 @@ func NewMovieRepository(dbpool *pgxpool.Pool, rdb *redis.Client) *MovieRepositor
 func (m *MovieRepository) GetMovieSchedules(ctx context.Context, movieId int) (models.MovieSchedule, error) {
 	sql := `
 		SELECT
-			cs.id, cs.show_date, t.show_time, l.show_location, c.cinema_name, c.cinema_img
+			s.id, s.show_date, t.show_time, l.show_location, c.cinema_name, c.cinema_img,
+			s.format, s.audio_language, s.subtitle_language, ` + schedulePriceExpr + `,
+			` + scheduleStartsAtExpr + `, l.timezone
 		FROM
-			schedule cs
+			schedule s
 		JOIN
-			movies m ON m.id = cs.movie_id
+			movies m ON m.id = s.movie_id
 		JOIN
-			jam_tayang t ON t.id = cs.time_id
+			jam_tayang t ON t.id = s.time_id
 		JOIN
-			lokasi_tayang l ON l.id = cs.location_id
+			lokasi_tayang l ON l.id = s.location_id
 		JOIN
-			cinema_tayang c ON c.id = cs.cinema_id
+			cinema_tayang c ON c.id = s.cinema_id
+		JOIN
+			screen_formats f ON f.code = s.format
 		WHERE
-			m.id = $1
+			m.id = $1 AND ` + liveScheduleCond + `
 		ORDER BY
-			cs.id ASC
+			s.id ASC
 	`
 	rows, err := m.dbpool.Query(ctx, sql, movieId)
 	if err != nil {
@@ -53,6 +59,8 @@ func (m *MovieRepository) GetMovieSchedules(ctx context.Context, movieId int) (m
 	var schedules []models.Schedule
 	for rows.Next() {
 		var s models.Schedule
+		var startsAt time.Time
+		var timezone string
 		if err := rows.Scan(
 			&s.ScheduleID,
 			&s.Date,
@@ -60,9 +68,16 @@ func (m *MovieRepository) GetMovieSchedules(ctx context.Context, movieId int) (m
 			&s.Location,
 			&s.Cinema,
 			&s.CinemaImg,
+			&s.Format,
+			&s.AudioLanguage,
+			&s.SubtitleLanguage,
+			&s.Price,
+			&startsAt,
+			&timezone,
 		); err != nil {
 			return models.MovieSchedule{}, err
 		}
+		s.LocalTime = models.NewLocalTime(startsAt, timezone)
 		schedules = append(schedules, s)
 	}
 
@@ -73,17 +88,29 @@ func (m *MovieRepository) GetMovieSchedules(ctx context.Context, movieId int) (m
 	return movieSchedule, nil
 }
 
-func (m *MovieRepository) GetMovieScheduleFilter(ctx context.Context, movieId, timeId, locationId int, date string) ([]models.MovieScheduleFilter, error) {
+func (m *MovieRepository) GetMovieScheduleFilter(ctx context.Context, movieId, timeId, locationId int, date string, screening models.ScreeningFilter) ([]models.MovieScheduleFilter, error) {
 	sql := `
-		SELECT s.id, ct.id, ct.cinema_name, ct.cinema_img
+		SELECT s.id, ct.id, ct.cinema_name, ct.cinema_img,
+			s.format, s.audio_language, s.subtitle_language, ` + schedulePriceExpr + `,
+			` + scheduleStartsAtExpr + `, l.timezone
 		FROM schedule s
+		JOIN movies m ON m.id = s.movie_id
 		JOIN cinema_tayang ct ON ct.id = s.cinema_id
+		JOIN jam_tayang t ON t.id = s.time_id
+		JOIN lokasi_tayang l ON l.id = s.location_id
+		JOIN screen_formats f ON f.code = s.format
 		WHERE s.movie_id = $1
+		AND ` + liveScheduleCond + `
 		AND s.show_date = $2
 		AND s.time_id = $3
 		AND s.location_id = $4
 	`
-	rows, err := m.dbpool.Query(ctx, sql, movieId, date, timeId, locationId)
+	conds, args := appendScreeningConds(nil, []any{movieId, date, timeId, locationId}, screening)
+	for _, cond := range conds {
+		sql += " AND " + cond
+	}
+
+	rows, err := m.dbpool.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -92,14 +119,23 @@ func (m *MovieRepository) GetMovieScheduleFilter(ctx context.Context, movieId, t
 	var schedules []models.MovieScheduleFilter
 	for rows.Next() {
 		var schedule models.MovieScheduleFilter
+		var startsAt time.Time
+		var timezone string
 		if err := rows.Scan(
 			&schedule.ScheduleID,
 			&schedule.CinemaID,
 			&schedule.CinemaName,
 			&schedule.CinemaImg,
+			&schedule.Format,
+			&schedule.AudioLanguage,
+			&schedule.SubtitleLanguage,
+			&schedule.Price,
+			&startsAt,
+			&timezone,
 		); err != nil {
 			return nil, err
 		}
+		schedule.LocalTime = models.NewLocalTime(startsAt, timezone)
 
 		schedules = append(schedules, schedule)
 	}
@@ -107,6 +143,100 @@ func (m *MovieRepository) GetMovieScheduleFilter(ctx context.Context, movieId, t
 	return schedules, nil
 }
 
+func (m *MovieRepository) GetMovieShowtimes(ctx context.Context, movieId int, screening models.ScreeningFilter) (models.MovieShowtimes, error) {
+	conds, args := appendScreeningConds(
+		[]string{"s.movie_id = $1", liveScheduleCond, upcomingScheduleCond},
+		[]any{movieId},
+		screening,
+	)
+	sql := `
+		SELECT
+			s.id, s.show_date, l.id, l.show_location, c.id, c.cinema_name, c.cinema_img, t.show_time,
+			s.format, s.audio_language, s.subtitle_language, ` + schedulePriceExpr + `,
+			` + scheduleStartsAtExpr + `, l.timezone,
+			(SELECT COUNT(*) FROM seats) - (
+				SELECT COUNT(*)
+				FROM orders_seats os
+				JOIN orders o ON o.id = os.order_id
+				WHERE o.schedule_id = s.id
+			) AS remaining_seats
+		FROM
+			schedule s
+		JOIN
+			movies m ON m.id = s.movie_id
+		JOIN
+			jam_tayang t ON t.id = s.time_id
+		JOIN
+			lokasi_tayang l ON l.id = s.location_id
+		JOIN
+			cinema_tayang c ON c.id = s.cinema_id
+		JOIN
+			screen_formats f ON f.code = s.format
+		WHERE
+			` + strings.Join(conds, " AND ") + `
+		ORDER BY
+			s.show_date ASC, l.id ASC, c.id ASC, t.show_time ASC
+	`
+	rows, err := m.dbpool.Query(ctx, sql, args...)
+	if err != nil {
+		return models.MovieShowtimes{}, err
+	}
+	defer rows.Close()
+
+	// rows are ordered by date, location and cinema so each group
+	// only has to be compared against the last one appended
+	result := models.MovieShowtimes{MovieID: uint32(movieId), Dates: []models.ShowtimeDate{}}
+	for rows.Next() {
+		var (
+			showDate time.Time
+			loc      models.ShowtimeLocation
+			cinema   models.ShowtimeCinema
+			show     models.Showtime
+			startsAt time.Time
+			timezone string
+		)
+		if err := rows.Scan(
+			&show.ScheduleID,
+			&showDate,
+			&loc.LocationID,
+			&loc.Location,
+			&cinema.CinemaID,
+			&cinema.CinemaName,
+			&cinema.CinemaImg,
+			&show.Time,
+			&show.Format,
+			&show.AudioLanguage,
+			&show.SubtitleLanguage,
+			&show.Price,
+			&startsAt,
+			&timezone,
+			&show.RemainingSeats,
+		); err != nil {
+			return models.MovieShowtimes{}, err
+		}
+		show.LocalTime = models.NewLocalTime(startsAt, timezone)
+
+		dates := result.Dates
+		if len(dates) == 0 || !dates[len(dates)-1].Date.Equal(showDate) {
+			result.Dates = append(result.Dates, models.ShowtimeDate{Date: showDate})
+		}
+		date := &result.Dates[len(result.Dates)-1]
+
+		if len(date.Locations) == 0 || date.Locations[len(date.Locations)-1].LocationID != loc.LocationID {
+			date.Locations = append(date.Locations, loc)
+		}
+		location := &date.Locations[len(date.Locations)-1]
+
+		if len(location.Cinemas) == 0 || location.Cinemas[len(location.Cinemas)-1].CinemaID != cinema.CinemaID {
+			location.Cinemas = append(location.Cinemas, cinema)
+		}
+		lastCinema := &location.Cinemas[len(location.Cinemas)-1]
+		lastCinema.Showtimes = append(lastCinema.Showtimes, show)
+	}
+
+	return result, nil
+}
+
 func (m *MovieRepository) updateMovieCasts(tx pgx.Tx, ctx context.Context, movieID uint32, newCastsCSV string) error {
 	// Normalize new casts
 	newCasts := []string{}
@@ -197,6 +327,19 @@ func (m *MovieRepository) UpdateMovie(
 	ctx context.Context,
 	id int,
 ) error {
+	var rawCredits string
+	if newBody.Credits != nil {
+		rawCredits = *newBody.Credits
+	}
+	credits, err := ParseCredits(rawCredits)
+	if err != nil {
+		return err
+	}
+	stalePeople, err := m.movieSuggestionPeople(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	tx, err := m.dbpool.Begin(ctx)
 	if err != nil {
 		return err
@@ -279,27 +422,48 @@ func (m *MovieRepository) UpdateMovie(
 		}
 	}
 
-	// Casts update
-	if newBody.Casts != nil {
+	// Casts update, structured credits win over the CSV
+	if newBody.Casts != nil && credits.Cast == nil {
 		_, err := tx.Exec(ctx, "DELETE FROM movies_casts WHERE movie_id = $1", id)
 		if err != nil {
 			return err
 		}
-		if err := m.insertMovieCasts(tx, ctx, uint32(id), *newBody.Casts); err != nil {
+		if err := m.insertMovieCasts(tx, ctx, uint32(id), splitNames(*newBody.Casts)); err != nil {
 			return err
 		}
 	}
 
+	if err := m.replaceMovieCredits(tx, ctx, uint32(id), credits); err != nil {
+		return err
+	}
+	if credits.Crew == nil && (newBody.DirectorName != nil || newBody.DirectorID != nil) {
+		if err := m.syncDirectorCrew(tx, ctx, uint32(id)); err != nil {
+			return err
+		}
+	}
+
+	if err := m.refreshSearchDocument(tx, ctx, uint32(id)); err != nil {
+		return err
+	}
+
 	// Bust redis caches
-	redisKeyUpc := "archie:movies_upcomings"
-	redisKeyPop := "archie:movies_populars"
-	res, err := m.rdb.Del(ctx, redisKeyPop, redisKeyUpc).Result()
+	res, err := m.rdb.Del(ctx, upcomingsKeys()...).Result()
 	if err != nil {
 		log.Println(err)
 	}
 	log.Printf("Number of keys deleted: %d", res)
+	if err := utils.InvalidateCachePattern(m.rdb, ctx, "archie:movies_populars_*"); err != nil {
+		log.Println(err)
+	}
 
-	return tx.Commit(ctx)
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if err := m.syncMovieSuggestions(ctx, id, stalePeople); err != nil {
+		log.Println(err)
+	}
+	return nil
 }
 
 // 	// tx, err := m.dbpool.Begin(ctx)
@@ -403,113 +567,744 @@ func (m *MovieRepository) UpdateMovie(
 // 	// return tx.Commit(ctx)
 // }
 
-func (m *MovieRepository) GetMovieWithGenrePageSearch(ctx context.Context, q, genreName string, limit, offset int) ([]models.MovieFilter, error) {
-	baseSQL := `
-		SELECT DISTINCT
-			m.id, m.title, m.poster_path, m.release_date, m.runtime
-		FROM
-			movies m
-		JOIN
-			movies_genres mg ON mg.movie_id = m.id
-		WHERE
-			m.title ILIKE $1
+// refreshSearchDocument rebuilds the weighted full-text document of a movie,
+// title A, director B, casts C and overview D
+func (m *MovieRepository) refreshSearchDocument(tx pgx.Tx, ctx context.Context, movieID uint32) error {
+	sql := `
+		UPDATE movies m
+		SET search_document =
+			setweight(to_tsvector('simple', coalesce(m.title, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce((
+				SELECT string_agg(mt.title, ' ') FROM movie_translations mt WHERE mt.movie_id = m.id
+			), '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce((SELECT d.name FROM directors d WHERE d.id = m.director_id), '')), 'B') ||
+			setweight(to_tsvector('simple', coalesce((
+				SELECT string_agg(c.name, ' ')
+				FROM movies_casts mc
+				JOIN casts c ON c.id = mc.cast_id
+				WHERE mc.movie_id = m.id
+			), '')), 'C') ||
+			setweight(to_tsvector('simple', coalesce(m.overview, '')), 'D') ||
+			setweight(to_tsvector('simple', coalesce((
+				SELECT string_agg(mt.overview, ' ') FROM movie_translations mt WHERE mt.movie_id = m.id
+			), '')), 'D')
+		WHERE m.id = $1
 	`
+	_, err := tx.Exec(ctx, sql, movieID)
+	return err
+}
+
+// movieSearchSQL returns the match condition, the relevance expression and
+// the highlight columns for a search term bound to $param, full-text match
+// is ranked first and pg_trgm similarity catches typos. title and overview
+// are the expressions of the language searched in
+func movieSearchSQL(param int, title, overview string) (cond, relevance, highlights string) {
+	query := fmt.Sprintf("websearch_to_tsquery('simple', $%d)", param)
+	term := fmt.Sprintf("$%d", param)
+
+	castMatch := fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM movies_casts mc JOIN casts c ON c.id = mc.cast_id
+		WHERE mc.movie_id = m.id AND c.name %% %s
+	)`, term)
+
+	// ILIKE keeps partially typed titles matching like the previous search did
+	cond = fmt.Sprintf(
+		"(m.search_document @@ %[1]s OR %[4]s ILIKE '%%' || %[2]s || '%%' OR %[4]s %% %[2]s OR d.name %% %[2]s OR %[3]s)",
+		query, term, castMatch, title,
+	)
+	relevance = fmt.Sprintf(
+		"(ts_rank(m.search_document, %[1]s) + similarity(%[2]s, %[3]s))",
+		query, title, term,
+	)
+	highlights = fmt.Sprintf(`
+		ts_headline('simple', %[4]s, %[1]s, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+		ts_headline('simple', coalesce(%[5]s, ''), %[1]s, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2'),
+		ARRAY_REMOVE(ARRAY[
+			CASE WHEN to_tsvector('simple', %[4]s) @@ %[1]s OR %[4]s ILIKE '%%' || %[2]s || '%%' OR %[4]s %% %[2]s THEN 'title' END,
+			CASE WHEN to_tsvector('simple', coalesce(d.name, '')) @@ %[1]s OR d.name %% %[2]s THEN 'director' END,
+			CASE WHEN ts_filter(m.search_document, '{c}') @@ %[1]s OR %[3]s THEN 'cast' END,
+			CASE WHEN to_tsvector('simple', coalesce(%[5]s, '')) @@ %[1]s THEN 'overview' END
+		], NULL)`,
+		query, term, castMatch, title, overview,
+	)
+	return cond, relevance, highlights
+}
+
+// localizedSQL is column of movie m in the language bound to $param, or in
+// the default language when it is not translated
+func localizedSQL(column string, param int) string {
+	return fmt.Sprintf(
+		"COALESCE((SELECT mt.%[1]s FROM movie_translations mt WHERE mt.movie_id = m.id AND mt.language = $%[2]d), m.%[1]s)",
+		column, param,
+	)
+}
 
-	args := []any{"%" + q + "%"} // $1
-	if genreName != "" {
-		genreID, err := m.castGenre(genreName)
+var ErrInvalidGenre = errors.New("invalid genre")
+
+// movieSortColumns maps the sort query to its column, relevance only exists
+// when searching with q. popularity is the score /movies/popular ranks by over
+// the default window
+var movieSortColumns = map[string]string{
+	"relevance":    "relevance",
+	"release_date": "m.release_date",
+	"popularity":   "COALESCE(p.score, 0)",
+	"title":        "m.title",
+	"rating":       "m.rating_avg",
+}
+
+func (m *MovieRepository) GetMovieWithGenrePageSearch(ctx context.Context, query models.MovieQuery, limit, offset int, lang string) ([]models.MovieFilter, int, error) {
+	scores, err := windowedPopularity(ctx, m.rdb, PopularityDefaultWindow)
+	if err != nil {
+		return nil, 0, err
+	}
+	movieIds, movieScores := splitScores(scores)
+
+	// $1 and $2 are the popularity scores joined as p
+	conds := []string{"m.deleted_at IS NULL"}
+	args := []any{movieIds, movieScores}
+	columns := "m.id, m.title, m.poster_path, m.release_date, m.runtime, COALESCE(p.score, 0), m.rating_avg, m.rating_count, m.age_rating, " + movieGenresJSON
+
+	q := strings.TrimSpace(query.Q)
+	if q != "" {
+		args = append(args, q)
+		param := len(args)
+		title, overview := "m.title", "m.overview"
+		if lang != utils.DefaultLanguage {
+			args = append(args, lang)
+			title, overview = localizedSQL("title", len(args)), localizedSQL("overview", len(args))
+		}
+		cond, relevance, highlights := movieSearchSQL(param, title, overview)
+		conds = append(conds, cond)
+		columns += ", " + relevance + " AS relevance, " + highlights
+	}
+
+	genreCSV := query.Genres
+	if query.Genre != "" {
+		genreCSV += "," + query.Genre
+	}
+	var genreIDs []int
+	for genre := range strings.SplitSeq(genreCSV, ",") {
+		genre = strings.TrimSpace(genre)
+		if genre == "" {
+			continue
+		}
+		genreID, err := m.castGenre(ctx, genre)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
+		}
+		genreIDs = append(genreIDs, genreID)
+	}
+	if len(genreIDs) > 0 {
+		args = append(args, genreIDs)
+		if query.GenreMatch == "all" {
+			conds = append(conds, fmt.Sprintf(`(
+				SELECT COUNT(DISTINCT mg.genre_id) FROM movies_genres mg
+				WHERE mg.movie_id = m.id AND mg.genre_id = ANY($%d)
+			) = CARDINALITY($%d::int[])`, len(args), len(args)))
+		} else {
+			conds = append(conds, fmt.Sprintf(
+				"EXISTS (SELECT 1 FROM movies_genres mg WHERE mg.movie_id = m.id AND mg.genre_id = ANY($%d))", len(args),
+			))
 		}
-		if genreID != 0 {
-			baseSQL += " AND mg.genre_id = $2"
-			args = append(args, genreID) // $2
+	}
+
+	if query.YearFrom != 0 {
+		args = append(args, query.YearFrom)
+		conds = append(conds, fmt.Sprintf("EXTRACT(YEAR FROM m.release_date) >= $%d", len(args)))
+	}
+	if query.YearTo != 0 {
+		args = append(args, query.YearTo)
+		conds = append(conds, fmt.Sprintf("EXTRACT(YEAR FROM m.release_date) <= $%d", len(args)))
+	}
+	if query.RuntimeMin != 0 {
+		args = append(args, query.RuntimeMin)
+		conds = append(conds, fmt.Sprintf("m.runtime >= $%d", len(args)))
+	}
+	if query.RuntimeMax != 0 {
+		args = append(args, query.RuntimeMax)
+		conds = append(conds, fmt.Sprintf("m.runtime <= $%d", len(args)))
+	}
+	if city := strings.TrimSpace(query.City); city != "" {
+		args = append(args, escapeLike(city))
+		conds = append(conds, fmt.Sprintf(`EXISTS (
+			SELECT 1
+			FROM schedule s
+			JOIN jam_tayang t ON t.id = s.time_id
+			JOIN lokasi_tayang l ON l.id = s.location_id
+			WHERE s.movie_id = m.id AND s.cancelled_at IS NULL AND l.show_location ILIKE $%d AND %s
+		)`, len(args), upcomingScheduleCond))
+	}
+
+	sort := query.Sort
+	if sort == "" && q != "" {
+		sort = "relevance"
+	}
+	if sort == "relevance" && q == "" {
+		sort = ""
+	}
+	orderBy := "m.id ASC"
+	if column, ok := movieSortColumns[sort]; ok {
+		direction := "DESC"
+		if query.Order == "asc" || (query.Order == "" && sort == "title") {
+			direction = "ASC"
 		}
+		orderBy = fmt.Sprintf("%s %s, m.id ASC", column, direction)
 	}
 
-	paramIdx := len(args) + 1
-	baseSQL += fmt.Sprintf(" AND deleted_at IS NULL ORDER BY m.id ASC LIMIT $%d OFFSET $%d", paramIdx, paramIdx+1)
+	fromSQL := `
+		FROM
+			movies m
+		LEFT JOIN
+			directors d ON d.id = m.director_id
+		LEFT JOIN
+			UNNEST($1::int[], $2::float8[]) AS p(movie_id, score) ON p.movie_id = m.id
+		WHERE
+			` + strings.Join(conds, " AND ")
+
+	var total int
+	if err := m.dbpool.QueryRow(ctx, "SELECT COUNT(*) "+fromSQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql := fmt.Sprintf(`
+		SELECT
+			%s
+		%s
+		ORDER BY
+			%s
+		LIMIT $%d OFFSET $%d
+	`, columns, fromSQL, orderBy, len(args)+1, len(args)+2)
 	args = append(args, limit, offset)
 
-	rows, err := m.dbpool.Query(ctx, baseSQL, args...)
+	rows, err := m.dbpool.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var movies []models.MovieFilter
+	movies := []models.MovieFilter{}
 	for rows.Next() {
 		var movie models.MovieFilter
-		if err := rows.Scan(
+		dest := []any{
 			&movie.ID,
 			&movie.Title,
 			&movie.PosterPath,
 			&movie.ReleaseDate,
 			&movie.Runtime,
-		); err != nil {
-			return nil, err
+			&movie.Popularity,
+			&movie.Rating.Average,
+			&movie.Rating.Count,
+			&movie.AgeRating,
+			&movie.Genres,
 		}
-
-		genres, err := m.fetchGenres(ctx, int(movie.ID))
-		if err != nil {
-			return nil, err
+		if q != "" {
+			movie.Highlight = &models.SearchHighlight{}
+			dest = append(dest,
+				&movie.Relevance,
+				&movie.Highlight.Title,
+				&movie.Highlight.Overview,
+				&movie.Highlight.MatchedFields,
+			)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, err
 		}
-		movie.Genres = genres
 
 		movies = append(movies, movie)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := localizeMovies(ctx, m.dbpool, lang, movies, movieFilterFields); err != nil {
+		return nil, 0, err
+	}
 
-	return movies, nil
+	return movies, total, nil
 }
 
+// SoftDeleteMovie trashes a movie, cancels its schedules that have not
+// started yet and notifies everyone holding tickets for them. restoring the
+// movie later does not bring the cancelled schedules back
 func (m *MovieRepository) SoftDeleteMovie(ctx context.Context, movieId int) (pgconn.CommandTag, error) {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer tx.Rollback(ctx)
+
 	sql := `
 		UPDATE 
 			movies
 		SET
 			deleted_at = now()
 		WHERE
-			id = $1
+			id = $1 AND deleted_at IS NULL
 	`
+	ctag, err := tx.Exec(ctx, sql, movieId)
+	if err != nil || ctag.RowsAffected() == 0 {
+		return ctag, err
+	}
+
+	cancelled, err := m.cancelMovieSchedules(tx, ctx, movieId)
+	if err != nil {
+		return ctag, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return ctag, err
+	}
+	log.Printf("movie %d deleted, %d upcoming schedules cancelled", movieId, len(cancelled))
+	if err := m.dropScheduleSales(ctx, cancelled); err != nil {
+		log.Println(err)
+	}
+
+	m.dropScheduleCaches(ctx)
+	if err := utils.InvalidateCachePattern(m.rdb, ctx, "archie:movies_populars_*"); err != nil {
+		log.Println(err)
+	}
+
+	if err := m.syncMovieSuggestions(ctx, movieId, nil); err != nil {
+		log.Println(err)
+	}
+	return ctag, nil
+}
+
+// cancelMovieSchedules cancels the upcoming schedules of a movie, leaves a
+// notification for every order placed on them and returns their ids
+func (m *MovieRepository) cancelMovieSchedules(tx pgx.Tx, ctx context.Context, movieId int) ([]int, error) {
+	sql := `
+		UPDATE
+			schedule s
+		SET
+			cancelled_at = now()
+		FROM
+			jam_tayang t, lokasi_tayang l
+		WHERE
+			t.id = s.time_id AND l.id = s.location_id
+		AND
+			s.movie_id = $1 AND s.cancelled_at IS NULL
+		AND
+			` + upcomingScheduleCond + `
+		RETURNING
+			s.id
+	`
+	rows, err := tx.Query(ctx, sql, movieId)
+	if err != nil {
+		return nil, err
+	}
+	scheduleIds, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil || len(scheduleIds) == 0 {
+		return scheduleIds, err
+	}
+
+	notifySQL := `
+		INSERT INTO notifications (user_id, order_id, kind, title, message)
+		SELECT
+			o.user_id, o.id, '` + models.NotificationScheduleCancelled + `', 'Showtime cancelled',
+			format(
+				'%s on %s at %s in %s has been cancelled because the movie was withdrawn. Order #%s can no longer be used, please contact the cinema about it.',
+				m.title, to_char(s.show_date, 'YYYY-MM-DD'), to_char(t.show_time::time, 'HH24:MI'), c.cinema_name, o.id
+			)
+		FROM
+			orders o
+		JOIN
+			schedule s ON s.id = o.schedule_id
+		JOIN
+			movies m ON m.id = s.movie_id
+		JOIN
+			jam_tayang t ON t.id = s.time_id
+		JOIN
+			cinema_tayang c ON c.id = s.cinema_id
+		WHERE
+			s.movie_id = $1 AND s.cancelled_at IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM notifications n
+			WHERE n.order_id = o.id AND n.kind = '` + models.NotificationScheduleCancelled + `'
+		)
+	`
+	if _, err := tx.Exec(ctx, notifySQL, movieId); err != nil {
+		return nil, err
+	}
+
+	return scheduleIds, nil
+}
+
+var (
+	ErrMovieNotFound   = errors.New("movie not found")
+	ErrMovieHasOrders  = errors.New("movie has orders")
+	ErrNothingToUpdate = errors.New("nothing to update")
+)
+
+func (m *MovieRepository) GetTrashedMovies(ctx context.Context, limit, offset int) ([]models.TrashedMovie, int, error) {
+	var total int
+	if err := m.dbpool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM movies WHERE deleted_at IS NOT NULL",
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql := `
+		SELECT
+			m.id, m.title, m.poster_path, m.release_date, m.deleted_at,
+			EXISTS (
+				SELECT 1 FROM orders o JOIN schedule s ON s.id = o.schedule_id
+				WHERE s.movie_id = m.id
+			)
+		FROM
+			movies m
+		WHERE
+			m.deleted_at IS NOT NULL
+		ORDER BY
+			m.deleted_at DESC, m.id ASC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := m.dbpool.Query(ctx, sql, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movies := []models.TrashedMovie{}
+	for rows.Next() {
+		var movie models.TrashedMovie
+		if err := rows.Scan(
+			&movie.ID,
+			&movie.Title,
+			&movie.PosterPath,
+			&movie.ReleaseDate,
+			&movie.DeletedAt,
+			&movie.HasOrders,
+		); err != nil {
+			return nil, 0, err
+		}
+		movies = append(movies, movie)
+	}
+
+	return movies, total, rows.Err()
+}
+
+func collectIDs(rows pgx.Rows) ([]int, error) {
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// RestoreMovies un-deletes the trashed movies among ids and returns the ids restored
+func (m *MovieRepository) RestoreMovies(ctx context.Context, ids []int) ([]int, error) {
+	sql := `
+		UPDATE
+			movies
+		SET
+			deleted_at = NULL, updated_at = current_timestamp
+		WHERE
+			id = ANY($1) AND deleted_at IS NOT NULL
+		RETURNING
+			id
+	`
+	rows, err := m.dbpool.Query(ctx, sql, ids)
+	if err != nil {
+		return nil, err
+	}
+	restored, err := collectIDs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(restored) == 0 {
+		return restored, nil
+	}
+
+	res, err := m.rdb.Del(ctx, upcomingsKeys()...).Result()
+	if err != nil {
+		log.Println(err)
+	}
+	log.Printf("Number of keys deleted: %d", res)
+	if err := utils.InvalidateCachePattern(m.rdb, ctx, "archie:movies_populars_*"); err != nil {
+		log.Println(err)
+	}
+	if err := utils.InvalidateCachePattern(m.rdb, ctx, "archie:schedules_*"); err != nil {
+		log.Println(err)
+	}
+	for _, id := range restored {
+		if err := m.syncMovieSuggestions(ctx, id, nil); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return restored, nil
+}
+
+// PurgeMovies permanently deletes the trashed movies among ids together with
+// their genres, casts, schedules and gallery. nothing is purged if any of them
+// has orders. orphans are the uploaded images nothing shows anymore, for the
+// caller to remove
+func (m *MovieRepository) PurgeMovies(ctx context.Context, ids []int) ([]int, []string, error) {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id FROM movies
+		WHERE id = ANY($1) AND deleted_at IS NOT NULL
+		FOR UPDATE
+	`, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	trashed, err := collectIDs(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(trashed) == 0 {
+		return trashed, nil, nil
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT DISTINCT s.movie_id
+		FROM orders o
+		JOIN schedule s ON s.id = o.schedule_id
+		WHERE s.movie_id = ANY($1)
+		ORDER BY s.movie_id
+	`, trashed)
+	if err != nil {
+		return nil, nil, err
+	}
+	withOrders, err := collectIDs(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(withOrders) > 0 {
+		return nil, nil, fmt.Errorf("%w: %v", ErrMovieHasOrders, withOrders)
+	}
+
+	// the images shown by the movies, their gallery goes with them
+	rows, err = tx.Query(ctx, `
+		SELECT kind, url FROM movie_media WHERE movie_id = ANY($1) AND kind <> 'trailer'
+		UNION
+		SELECT 'poster', poster_path FROM movies WHERE id = ANY($1) AND poster_path IS NOT NULL
+		UNION
+		SELECT 'backdrop', backdrop_path FROM movies WHERE id = ANY($1) AND backdrop_path IS NOT NULL
+	`, trashed)
+	if err != nil {
+		return nil, nil, err
+	}
+	images, err := pgx.CollectRows(rows, pgx.RowToStructByPos[mediaFile])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, sql := range []string{
+		"DELETE FROM schedule WHERE movie_id = ANY($1)",
+		"DELETE FROM movies_genres WHERE movie_id = ANY($1)",
+		"DELETE FROM movies_casts WHERE movie_id = ANY($1)",
+		"DELETE FROM movies WHERE id = ANY($1)",
+	} {
+		if _, err := tx.Exec(ctx, sql, trashed); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var orphans []string
+	for _, image := range images {
+		orphan, err := orphanedMediaFile(tx, ctx, image.Kind, image.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+		if orphan != "" {
+			orphans = append(orphans, orphan)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return trashed, orphans, nil
+}
+
+// mediaFile is an image of kind stored at URL
+type mediaFile struct {
+	Kind, URL string
+}
+
+// GetPopularMovies ranks live movies by their tickets sold in the last
+// windowDays with recent days weighing more, the provider popularity breaks
+// ties so the list is not empty on a quiet week
+func (m *MovieRepository) GetPopularMovies(ctx context.Context, windowDays int, lang string) ([]models.MovieFilter, error) {
+	redisKey := fmt.Sprintf("archie:movies_populars_%d_%s", windowDays, lang)
+	var populars []models.MovieFilter
+
+	isExist, err := utils.CacheGet(m.rdb, ctx, redisKey, &populars)
+	if err != nil {
+		utils.PrintError("redis> REDIS ERROR", 20, err)
+	}
+	if isExist {
+		return populars, nil
+	}
+
+	scores, err := windowedPopularity(ctx, m.rdb, windowDays)
+	if err != nil {
+		return nil, err
+	}
+	movieIds, movieScores := splitScores(scores)
+
+	sql := `
+		SELECT
+			m.id, m.title, m.poster_path, m.release_date, m.rating_avg, m.rating_count, m.age_rating, COALESCE(p.score, 0),
+			` + movieGenresJSON + `
+		FROM movies m
+		LEFT JOIN UNNEST($1::int[], $2::float8[]) AS p(movie_id, score) ON p.movie_id = m.id
+		WHERE m.deleted_at IS NULL
+		ORDER BY COALESCE(p.score, 0) DESC, m.popularity DESC NULLS LAST, m.id ASC
+		LIMIT $3
+	`
+
+	rows, err := m.dbpool.Query(ctx, sql, movieIds, movieScores, popularLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movie models.MovieFilter
+		if err := rows.Scan(
+			&movie.ID,
+			&movie.Title,
+			&movie.PosterPath,
+			&movie.ReleaseDate,
+			&movie.Rating.Average,
+			&movie.Rating.Count,
+			&movie.AgeRating,
+			&movie.Popularity,
+			&movie.Genres,
+		); err != nil {
+			return nil, err
+		}
+		populars = append(populars, movie)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := localizeMovies(ctx, m.dbpool, lang, populars, movieFilterFields); err != nil {
+		return nil, err
+	}
+
+	// short lived as paid orders keep moving the scores
+	expiration := 10 * time.Minute
+	if err := utils.CacheSet(m.rdb, ctx, redisKey, populars, expiration); err != nil {
+		utils.PrintError(
+			fmt.Sprintf("redis> UNABLE TO SET %s", redisKey), 20, err,
+		)
+	}
+
+	return populars, nil
+}
+
+// upcomingsKey is the cached upcoming list in lang
+func upcomingsKey(lang string) string {
+	return "archie:movies_upcomings_" + lang
+}
+
+// upcomingsKeys are the cached upcoming lists of every language
+func upcomingsKeys() []string {
+	keys := make([]string, 0, len(utils.Languages))
+	for _, lang := range utils.Languages {
+		keys = append(keys, upcomingsKey(lang))
+	}
+	return keys
+}
+
+// GetUpcomingMovies lists movies releasing after today that have no
+// showtime yet
+func (m *MovieRepository) GetUpcomingMovies(ctx context.Context, lang string) ([]models.MovieFilter, error) {
+	redisKey := upcomingsKey(lang)
+	var cached []models.MovieFilter
+
+	isExist, err := utils.CacheGet(m.rdb, ctx, redisKey, &cached)
+	if err != nil {
+		utils.PrintError("redis> REDIS ERROR", 20, err)
+	}
+	if isExist {
+		return cached, nil
+	}
+
+	// var upcomings []models.MovieFilter
+	// titles already on sale are listed as now showing instead
+	upcomings, err := m.GetAllMovies(ctx, `
+		AND m.release_date > CURRENT_DATE
+		AND NOT EXISTS (
+			SELECT 1 FROM schedule s WHERE s.movie_id = m.id AND s.cancelled_at IS NULL
+		)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeMovies(ctx, m.dbpool, lang, upcomings, movieFilterFields); err != nil {
+		return nil, err
+	}
 
-	redisKeyUpc := "archie:movies_upcomings"
-	redisKeyPop := "archie:movies_populars"
-	res, err := m.rdb.Del(ctx, redisKeyPop, redisKeyUpc).Result()
-	if err != nil {
-		log.Println(err)
+	// release dates pass without any write, so it can not live for long
+	expiration := 1 * time.Hour
+	if err := utils.CacheSet(m.rdb, ctx, redisKey, upcomings, expiration); err != nil {
+		utils.PrintError(
+			fmt.Sprintf("redis> UNABLE TO SET %s", redisKey), 20, err,
+		)
 	}
-	log.Printf("Number of keys deleted: %d", res)
 
-	return m.dbpool.Exec(ctx, sql, movieId)
+	return upcomings, nil
 }
 
-func (m *MovieRepository) GetPopularMovies(ctx context.Context) ([]models.MovieFilter, error) {
-	redisKey := "archie:movies_populars"
-	var populars []models.MovieFilter
+// GetNowShowingMovies lists movies with at least one upcoming showtime,
+// limited to a city when given. the cache key lives under the schedules prefix
+// so every schedule change drops it
+func (m *MovieRepository) GetNowShowingMovies(ctx context.Context, city, lang string) ([]models.MovieFilter, error) {
+	city = strings.TrimSpace(city)
+	redisKey := fmt.Sprintf("archie:schedules_now_showing_%s_%s", lang, strings.ToLower(city))
+	var cached []models.MovieFilter
 
-	isExist, err := utils.CacheGet(m.rdb, ctx, redisKey, &populars)
+	isExist, err := utils.CacheGet(m.rdb, ctx, redisKey, &cached)
 	if err != nil {
 		utils.PrintError("redis> REDIS ERROR", 20, err)
 	}
 	if isExist {
-		return populars, nil
+		return cached, nil
 	}
 
 	sql := `
-		SELECT m.id, m.title, m.poster_path, m.release_date
-		FROM orders o
-		JOIN schedule s ON s.id = o.schedule_id
-		JOIN movies m ON m.id = s.movie_id
-		GROUP BY m.id, m.title, m.poster_path, m.release_date
-		ORDER BY COUNT(m.id) DESC
+		SELECT
+			m.id, m.title, m.poster_path, m.release_date, m.runtime, m.overview, COALESCE(d.name, ''),
+			m.rating_avg, m.rating_count, m.age_rating, ` + movieGenresJSON + `
+		FROM
+			movies m
+		LEFT JOIN
+			directors d ON d.id = m.director_id
+		WHERE
+			m.deleted_at IS NULL
+		AND EXISTS (
+			SELECT 1
+			FROM schedule s
+			JOIN jam_tayang t ON t.id = s.time_id
+			JOIN lokasi_tayang l ON l.id = s.location_id
+			WHERE s.movie_id = m.id AND s.cancelled_at IS NULL AND ` + upcomingScheduleCond + `
+			AND ($1 = '' OR l.show_location ILIKE $1)
+		)
+		ORDER BY
+			m.release_date DESC, m.id ASC
 	`
-
-	rows, err := m.dbpool.Query(ctx, sql)
+	rows, err := m.dbpool.Query(ctx, sql, escapeLike(city))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	nowShowing := []models.MovieFilter{}
 	for rows.Next() {
 		var movie models.MovieFilter
 		if err := rows.Scan(
@@ -517,64 +1312,42 @@ func (m *MovieRepository) GetPopularMovies(ctx context.Context) ([]models.MovieF
 			&movie.Title,
 			&movie.PosterPath,
 			&movie.ReleaseDate,
+			&movie.Runtime,
+			&movie.Overview,
+			&movie.Director,
+			&movie.Rating.Average,
+			&movie.Rating.Count,
+			&movie.AgeRating,
+			&movie.Genres,
 		); err != nil {
 			return nil, err
 		}
-
-		genres, err := m.fetchGenres(ctx, int(movie.ID))
-		if err != nil {
-			return nil, err
-		}
-
-		movie.Genres = append(movie.Genres, genres...)
-		populars = append(populars, movie)
-	}
-
-	expiration := 24 * time.Hour
-	if err := utils.CacheSet(m.rdb, ctx, redisKey, populars, expiration); err != nil {
-		utils.PrintError(
-			fmt.Sprintf("redis> UNABLE TO SET %s", redisKey), 20, err,
-		)
-	}
-
-	return populars, nil
-}
-
-func (m *MovieRepository) GetUpcomingMovies(ctx context.Context) ([]models.MovieFilter, error) {
-	redisKey := "archie:movies_upcomings"
-	var cached []models.MovieFilter
-
-	isExist, err := utils.CacheGet(m.rdb, ctx, redisKey, &cached)
-	if err != nil {
-		utils.PrintError("redis> REDIS ERROR", 20, err)
+		nowShowing = append(nowShowing, movie)
 	}
-	if isExist {
-		return cached, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-
-	// var upcomings []models.MovieFilter
-	upcomings, err := m.GetAllMovies(ctx, `
-		AND release_date + INTERVAL '1 months' > CURRENT_DATE
-	`)
-	if err != nil {
+	if err := localizeMovies(ctx, m.dbpool, lang, nowShowing, movieFilterFields); err != nil {
 		return nil, err
 	}
 
-	expiration := 24 * time.Hour
-	if err := utils.CacheSet(m.rdb, ctx, redisKey, upcomings, expiration); err != nil {
+	// the last showtime of a movie passes without any write
+	expiration := 15 * time.Minute
+	if err := utils.CacheSet(m.rdb, ctx, redisKey, nowShowing, expiration); err != nil {
 		utils.PrintError(
 			fmt.Sprintf("redis> UNABLE TO SET %s", redisKey), 20, err,
 		)
 	}
 
-	return upcomings, nil
+	return nowShowing, nil
 }
 
 func (m *MovieRepository) GetAllMovies(ctx context.Context, opts string) ([]models.MovieFilter, error) {
 	sql := `
 		SELECT 
 			m.id, m.title, m.poster_path, m.release_date, m.runtime, m.overview, d.name, 
-			STRING_AGG(c.name, ', ') casts
+			STRING_AGG(c.name, ', ') casts, m.rating_avg, m.rating_count, m.age_rating,
+			` + movieGenresJSON + `
 		FROM
 			movies m
 		JOIN
@@ -594,7 +1367,7 @@ func (m *MovieRepository) GetAllMovies(ctx context.Context, opts string) ([]mode
 
 	sql += `
 		GROUP BY
-			m.id, m.title, m.poster_path, m.release_date, m.runtime, m.overview, d.name
+			m.id, m.title, m.poster_path, m.release_date, m.runtime, m.overview, d.name, m.rating_avg, m.rating_count, m.age_rating
 		ORDER BY
 			m.id ASC
 	`
@@ -617,34 +1390,36 @@ func (m *MovieRepository) GetAllMovies(ctx context.Context, opts string) ([]mode
 			&movie.Overview,
 			&movie.Director,
 			&movie.Casts,
+			&movie.Rating.Average,
+			&movie.Rating.Count,
+			&movie.AgeRating,
+			&movie.Genres,
 		); err != nil {
 			return nil, err
 		}
-
-		genres, err := m.fetchGenres(ctx, int(movie.ID))
-		if err != nil {
-			return nil, err
-		}
-		movie.Genres = append(movie.Genres, genres...)
-
 		movies = append(movies, movie)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	utils.PrintError(fmt.Sprintf("SQL QUERY \n%s", sql), 20, nil)
 
 	return movies, nil
 }
 
-func (m *MovieRepository) GetMovieDetail(ctx context.Context, movieId int) (models.Movie, error) {
+func (m *MovieRepository) GetMovieDetail(ctx context.Context, movieId int, lang string) (models.Movie, error) {
 	sql := `
 		SELECT
-			m.id, m.title, m.backdrop_path, m.poster_path, m.release_date, m.runtime, m.overview, d.name
+			m.id, m.title, m.backdrop_path, m.poster_path, m.release_date, m.runtime, m.overview, d.name, d.person_id,
+			m.rating_avg, m.rating_count, m.age_rating,
+			` + movieGenresJSON + `, ` + movieCastsJSON + `, ` + movieCreditsJSON + `
 		FROM 
 			movies m
 		JOIN 
 			directors d ON d.id = m.director_id
 		WHERE
-			m.id = $1
+			m.id = $1 AND m.deleted_at IS NULL
 	`
 
 	var movie models.Movie
@@ -657,121 +1432,132 @@ func (m *MovieRepository) GetMovieDetail(ctx context.Context, movieId int) (mode
 		&movie.Runtime,
 		&movie.Overview,
 		&movie.DirectorName,
+		&movie.DirectorPersonID,
+		&movie.Rating.Average,
+		&movie.Rating.Count,
+		&movie.AgeRating,
+		&movie.Genres,
+		&movie.Casts,
+		&movie.Credits,
 	); err != nil {
 		return models.Movie{}, err
 	}
 
-	genres, err := m.fetchGenres(ctx, movieId)
+	media, err := m.fetchMedia(ctx, movieId)
 	if err != nil {
 		return models.Movie{}, err
 	}
-	casts, err := m.fetchCasts(ctx, movieId)
+	movie.Media = media
+
+	collection, err := m.fetchMovieCollection(ctx, movieId, lang)
 	if err != nil {
 		return models.Movie{}, err
 	}
-	movie.Genres = append(movie.Genres, genres...)
-	movie.Casts = append(movie.Casts, casts...)
+	movie.Collection = collection
 
-	return movie, nil
+	detail := []models.Movie{movie}
+	if err := localizeMovies(ctx, m.dbpool, lang, detail, func(m *models.Movie) localized {
+		return localized{m.ID, &m.Title, &m.Overview, m.Genres}
+	}); err != nil {
+		return models.Movie{}, err
+	}
+	return detail[0], nil
 }
 
-func (m *MovieRepository) fetchCasts(ctx context.Context, movieId int) ([]models.Cast, error) {
-	sql := `
-		SELECT a.id, a.name
+// movieGenresJSON and movieCastsJSON aggregate the genres and casts of movie m
+// into one JSON column each, so listings read them in the same query as the
+// movies instead of one more query per row
+const movieGenresJSON = `COALESCE((
+	SELECT JSON_AGG(JSON_BUILD_OBJECT('id', g.id, 'name', g.genre_name) ORDER BY g.id)
+	FROM movies_genres mg
+	JOIN genres g ON g.id = mg.genre_id
+	WHERE mg.movie_id = m.id
+), '[]')`
+
+const movieCastsJSON = `COALESCE((
+	SELECT JSON_AGG(JSON_BUILD_OBJECT('id', a.id, 'name', a.name) ORDER BY ma.billing_order NULLS LAST, a.id)
+	FROM movies_casts ma
+	JOIN casts a ON a.id = ma.cast_id
+	WHERE ma.movie_id = m.id
+), '[]')`
+
+// movieCreditsJSON is the cast of movie m by billing order with characters
+// and its crew by job
+const movieCreditsJSON = `JSON_BUILD_OBJECT(
+	'cast', COALESCE((
+		SELECT JSON_AGG(JSON_BUILD_OBJECT(
+			'person_id', a.id, 'name', a.name, 'character', ma.character_name, 'order', ma.billing_order
+		) ORDER BY ma.billing_order NULLS LAST, a.id)
 		FROM movies_casts ma
 		JOIN casts a ON a.id = ma.cast_id
-		JOIN movies m ON m.id = ma.movie_id
-		WHERE m.id = $1;
-	`
-	rows, err := m.dbpool.Query(ctx, sql, movieId)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var casts []models.Cast
-	for rows.Next() {
-		var cast models.Cast
-		if err := rows.Scan(&cast.ID, &cast.Name); err != nil {
-			return nil, err
-		}
-
-		casts = append(casts, cast)
-	}
-
-	return casts, nil
+		WHERE ma.movie_id = m.id
+	), '[]'),
+	'crew', COALESCE((
+		SELECT JSON_AGG(JSON_BUILD_OBJECT(
+			'person_id', p.id, 'name', p.name, 'job', cr.job
+		) ORDER BY ARRAY_POSITION(ARRAY['director', 'writer', 'producer', 'composer'], cr.job::TEXT), cr.position, p.id)
+		FROM movie_crew cr
+		JOIN casts p ON p.id = cr.person_id
+		WHERE cr.movie_id = m.id
+	), '[]')
+)`
+
+// genreAliases are the names the seeded genres are also known by, keyed to
+// their ids so the aliases follow a renamed genre
+var genreAliases = map[string]int{
+	"action":          28,
+	"adventure":       12,
+	"animation":       16,
+	"comedy":          35,
+	"crime":           80,
+	"documentary":     99,
+	"drama":           18,
+	"family":          10751,
+	"fantasy":         14,
+	"history":         36,
+	"horror":          27,
+	"music":           10402,
+	"mystery":         9648,
+	"romance":         10749,
+	"sci-fi":          878,
+	"science-fiction": 878,
+	"science fiction": 878,
+	"scifi":           878,
+	"tv-movie":        10770,
+	"tvmovie":         10770,
+	"tv":              10770,
+	"thriller":        53,
+	"war":             10752,
+	"western":         37,
 }
 
-func (m *MovieRepository) fetchGenres(ctx context.Context, movieId int) ([]models.Genre, error) {
-	sql := `
-		SELECT g.id, g.genre_name
-		FROM movies_genres mg
-		JOIN genres g ON g.id = mg.genre_id
-		JOIN movies m ON m.id = mg.movie_id
-		WHERE m.id = $1;
-	`
-	rows, err := m.dbpool.Query(ctx, sql, movieId)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var genres []models.Genre
-	for rows.Next() {
-		var genre models.Genre
-		if err := rows.Scan(&genre.ID, &genre.Name); err != nil {
-			return nil, err
-		}
-
-		genres = append(genres, genre)
-	}
-
-	return genres, nil
+// genreAlias is the seeded genre id known by name, 0 if there is none
+func genreAlias(name string) int {
+	return genreAliases[strings.ToLower(strings.TrimSpace(name))]
 }
 
-func (m *MovieRepository) castGenre(strGenre string) (int, error) {
-	switch strings.ToLower(strGenre) {
-	case "action":
-		return 28, nil
-	case "adventure":
-		return 12, nil
-	case "animation":
-		return 16, nil
-	case "comedy":
-		return 35, nil
-	case "crime":
-		return 80, nil
-	case "documentary":
-		return 99, nil
-	case "drama":
-		return 18, nil
-	case "family":
-		return 10751, nil
-	case "fantasy":
-		return 14, nil
-	case "history":
-		return 36, nil
-	case "horror":
-		return 27, nil
-	case "music":
-		return 10402, nil
-	case "mystery":
-		return 9648, nil
-	case "romance":
-		return 10749, nil
-	case "sci-fi", "science-fiction", "science fiction", "scifi":
-		return 878, nil
-	case "tv-movie", "tvmovie", "tv":
-		return 10770, nil
-	case "thriller":
-		return 53, nil
-	case "war":
-		return 10752, nil
-	case "western":
-		return 37, nil
-	default:
-		return 0, fmt.Errorf("invalid genre: %q", strGenre)
+// castGenre resolves a genre name, the seeded genres keep their aliases and
+// the others are looked up by name or translated name. an alias of a deleted
+// genre falls back to the name lookup
+func (m *MovieRepository) castGenre(ctx context.Context, strGenre string) (int, error) {
+	var id int
+	if err := m.dbpool.QueryRow(ctx, `
+		SELECT id FROM (
+			SELECT id, 0 AS rank FROM genres WHERE id = $2
+			UNION ALL
+			SELECT id, 1 FROM genres WHERE LOWER(genre_name) = LOWER($1)
+			UNION ALL
+			SELECT genre_id, 2 FROM genre_translations WHERE LOWER(name) = LOWER($1)
+		) found
+		ORDER BY rank
+		LIMIT 1`, strings.TrimSpace(strGenre), genreAlias(strGenre),
+	).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidGenre, strGenre)
+		}
+		return 0, err
 	}
+	return id, nil
 }
 
 func (m *MovieRepository) CreateMovie(
@@ -780,6 +1566,11 @@ func (m *MovieRepository) CreateMovie(
 	posterPath string,
 	ctx context.Context,
 ) (uint32, error) {
+	credits, err := ParseCredits(body.Credits)
+	if err != nil {
+		return 0, err
+	}
+
 	tx, err := m.dbpool.Begin(ctx)
 	if err != nil {
 		return 0, err
@@ -794,6 +1585,20 @@ func (m *MovieRepository) CreateMovie(
 	var args []any
 	argIndex := 1
 
+	// a crew director wins over director_name
+	directorId, hasDirector, err := m.creditsDirector(tx, ctx, credits)
+	if err != nil {
+		return 0, err
+	}
+	if hasDirector {
+		columns = append(columns, "director_id")
+		placeholders = append(placeholders, fmt.Sprintf("$%d", argIndex))
+		args = append(args, directorId)
+		argIndex++
+	} else if body.DirectorName == "" {
+		return 0, fmt.Errorf("%w: director_name or a crew director is required", ErrInvalidCredits)
+	}
+
 	for i := 0; i < rt.NumField(); i++ {
 		field := rt.Field(i)
 		value := rv.Field(i)
@@ -804,6 +1609,9 @@ func (m *MovieRepository) CreateMovie(
 		}
 
 		if formTag == "director_name" {
+			if hasDirector {
+				continue
+			}
 			directorId, err := m.insertDirectors(tx, ctx, value.String())
 			if err != nil {
 				return 0, err
@@ -815,7 +1623,7 @@ func (m *MovieRepository) CreateMovie(
 			continue
 		}
 
-		if field.Type.String() == "*multipart.FileHeader" || formTag == "genres" || formTag == "casts" {
+		if field.Type.String() == "*multipart.FileHeader" || formTag == "genres" || formTag == "casts" || formTag == "credits" {
 			continue
 		}
 
@@ -834,7 +1642,11 @@ func (m *MovieRepository) CreateMovie(
 
 		if formTag == "location" ||
 			formTag == "schedule_date" ||
-			formTag == "schedule_time" {
+			formTag == "schedule_time" ||
+			formTag == "format" ||
+			formTag == "audio_language" ||
+			formTag == "subtitle_language" ||
+			formTag == "price" {
 			continue
 		}
 
@@ -873,12 +1685,29 @@ func (m *MovieRepository) CreateMovie(
 	if _, err := m.insertMovieGenres(tx, ctx, newMovieID, body.Genres); err != nil {
 		return 0, err
 	}
-	// insert casts
-	log.Println(body.Casts)
-	if err := m.insertMovieCasts(tx, ctx, newMovieID, body.Casts); err != nil {
+	// insert casts, structured credits win over the CSV
+	if credits.Cast == nil {
+		if err := m.insertMovieCasts(tx, ctx, newMovieID, splitNames(body.Casts)); err != nil {
+			return 0, err
+		}
+	}
+	if credits.Crew == nil {
+		if err := m.syncDirectorCrew(tx, ctx, newMovieID); err != nil {
+			return 0, err
+		}
+	}
+	if err := m.replaceMovieCredits(tx, ctx, newMovieID, credits); err != nil {
+		return 0, err
+	}
+	if err := m.refreshSearchDocument(tx, ctx, newMovieID); err != nil {
 		return 0, err
 	}
-	if err := m.createMovieSchedule(tx, ctx, newMovieID, body.ScheduleDate, body.LocationID, body.TimeID); err != nil {
+	screening := models.Screening{
+		Format:           body.Format,
+		AudioLanguage:    body.AudioLanguage,
+		SubtitleLanguage: body.SubtitleLanguage,
+	}
+	if _, err := m.createMovieSchedule(tx, ctx, newMovieID, body.ScheduleDate, body.LocationID, body.TimeID, screening, body.Price); err != nil {
 		return 0, err
 	}
 
@@ -886,12 +1715,17 @@ func (m *MovieRepository) CreateMovie(
 		return 0, err
 	}
 
-	redisKey := "archie:movies_upcomings"
-	res, err := m.rdb.Del(ctx, redisKey).Result()
+	res, err := m.rdb.Del(ctx, upcomingsKeys()...).Result()
 	if err != nil {
 		log.Println(err)
 	}
 	log.Printf("Number of keys deleted: %d", res)
+	if err := utils.InvalidateCachePattern(m.rdb, ctx, "archie:schedules_*"); err != nil {
+		log.Println(err)
+	}
+	if err := m.syncMovieSuggestions(ctx, int(newMovieID), nil); err != nil {
+		log.Println(err)
+	}
 
 	return newMovieID, nil
 }
@@ -903,34 +1737,234 @@ func (m *MovieRepository) createMovieSchedule(
 	scheduleDate string,
 	locationId,
 	timeId []int,
-) error {
-	sql := `
+	screening models.Screening,
+	price int,
+) (int64, error) {
+	if screening.Format == "" {
+		screening.Format = "2D"
+	}
+	if screening.AudioLanguage == "" {
+		screening.AudioLanguage = "en"
+	}
+
+	columns := "movie_id, show_date, time_id, location_id, cinema_id, format, audio_language, subtitle_language"
+	placeholders := "$1, $2, $3, $4, $5, $6, $7, $8"
+	args := []any{movieId, scheduleDate, 0, 0, 0, screening.Format, screening.AudioLanguage, screening.SubtitleLanguage}
+	// leave price to its column default when not given
+	if price > 0 {
+		columns += ", price"
+		placeholders += ", $9"
+		args = append(args, price)
+	}
+
+	sql := fmt.Sprintf(`
 		INSERT INTO 
-			schedule(movie_id, show_date, time_id, location_id, cinema_id)
+			schedule(%s)
 		VALUES
-			($1, $2, $3, $4, $5)
+			(%s)
 		ON CONFLICT 
 			(movie_id, show_date, time_id, location_id, cinema_id)
 		DO NOTHING
-	`
+	`, columns, placeholders)
+
+	var inserted int64
 	for _, tId := range timeId {
 		for _, lId := range locationId {
 			for _, cId := range m.sliceCinemaID(lId) {
-				if _, err := tx.Exec(
-					ctx,
-					sql,
-					movieId,
-					scheduleDate,
-					tId,
-					lId,
-					cId,
-				); err != nil {
-					return err
+				args[2], args[3], args[4] = tId, lId, cId
+				ctag, err := tx.Exec(ctx, sql, args...)
+				if err != nil {
+					return 0, err
 				}
+				inserted += ctag.RowsAffected()
 			}
 		}
 	}
-	return nil
+	return inserted, nil
+}
+
+func (m *MovieRepository) CreateSchedules(ctx context.Context, body models.CreateScheduleBody) (int64, error) {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// trashed movies can not be scheduled. the movie row stays locked so
+	// concurrent calls see each other's showtimes below
+	var movieId int
+	if err := tx.QueryRow(ctx,
+		"SELECT id FROM movies WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", body.MovieID,
+	).Scan(&movieId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrMovieNotFound
+		}
+		return 0, err
+	}
+
+	// watchlisters are told once the movie gets showtimes again
+	var hadSchedules bool
+	sql := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM schedule s
+			JOIN jam_tayang t ON t.id = s.time_id
+			JOIN lokasi_tayang l ON l.id = s.location_id
+			WHERE s.movie_id = $1 AND s.cancelled_at IS NULL AND ` + upcomingScheduleCond + `
+		)
+	`
+	if err := tx.QueryRow(ctx, sql, body.MovieID).Scan(&hadSchedules); err != nil {
+		return 0, err
+	}
+
+	screening := models.Screening{
+		Format:           body.Format,
+		AudioLanguage:    body.AudioLanguage,
+		SubtitleLanguage: body.SubtitleLanguage,
+	}
+	inserted, err := m.createMovieSchedule(
+		tx, ctx, uint32(body.MovieID), body.ScheduleDate, body.LocationID, body.TimeID, screening, body.Price,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if !hadSchedules && inserted > 0 {
+		if _, err := m.notifyWatchlisters(tx, ctx, body.MovieID, body.ScheduleDate); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	if err := utils.InvalidateCachePattern(m.rdb, ctx, "archie:schedules_*"); err != nil {
+		log.Println(err)
+	}
+	// a scheduled movie is no longer upcoming
+	if !hadSchedules && inserted > 0 {
+		res, err := m.rdb.Del(ctx, upcomingsKeys()...).Result()
+		if err != nil {
+			log.Println(err)
+		}
+		log.Printf("Number of keys deleted: %d", res)
+	}
+
+	return inserted, nil
+}
+
+// WatchlistedMovieIDs reports which of ids the user has saved
+func (m *MovieRepository) WatchlistedMovieIDs(ctx context.Context, userId uint16, ids []uint32) (map[uint32]bool, error) {
+	saved := map[uint32]bool{}
+	if len(ids) == 0 {
+		return saved, nil
+	}
+
+	rows, err := m.dbpool.Query(ctx,
+		"SELECT movie_id FROM watchlists WHERE user_id = $1 AND movie_id = ANY($2)", userId, ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		saved[id] = true
+	}
+	return saved, rows.Err()
+}
+
+// notifyWatchlisters pings the users who saved a movie that its tickets are
+// on sale, once per run of the movie: users told since its last past
+// showtime are not told again
+func (m *MovieRepository) notifyWatchlisters(tx pgx.Tx, ctx context.Context, movieId int, scheduleDate string) (int64, error) {
+	sql := `
+		INSERT INTO notifications (user_id, movie_id, kind, title, message)
+		SELECT
+			wl.user_id, m.id, '` + models.NotificationTicketsOpen + `', 'Tickets are open',
+			format('Tickets for %s are now on sale, showing from %s.', m.title, to_char($2::date, 'YYYY-MM-DD'))
+		FROM
+			watchlists wl
+		JOIN
+			movies m ON m.id = wl.movie_id
+		WHERE
+			wl.movie_id = $1
+		AND NOT EXISTS (
+			SELECT 1 FROM notifications n
+			WHERE n.user_id = wl.user_id AND n.movie_id = wl.movie_id
+			AND n.kind = '` + models.NotificationTicketsOpen + `'
+			AND n.created_at > COALESCE((
+				SELECT MAX(` + scheduleStartsAtExpr + `)
+				FROM schedule s
+				JOIN jam_tayang t ON t.id = s.time_id
+				JOIN lokasi_tayang l ON l.id = s.location_id
+				WHERE s.movie_id = wl.movie_id AND s.cancelled_at IS NULL AND NOT ` + upcomingScheduleCond + `
+			), '-infinity')
+		)
+	`
+	ctag, err := tx.Exec(ctx, sql, movieId, scheduleDate)
+	return ctag.RowsAffected(), err
+}
+
+// updateSQL builds an UPDATE of table's row id setting the non-nil pointer
+// fields of body, each to the column of its db tag
+func updateSQL(table string, body any, id int) (string, []any, error) {
+	var setClauses []string
+	var args []any
+	argIndex := 1
+
+	rt := reflect.TypeOf(body)
+	rv := reflect.ValueOf(body)
+
+	for i := 0; i < rt.NumField(); i++ {
+		dbTag := rt.Field(i).Tag.Get("db")
+		value := rv.Field(i)
+		if dbTag == "" || value.IsNil() {
+			continue
+		}
+
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", dbTag, argIndex))
+		args = append(args, value.Interface())
+		argIndex++
+	}
+	if len(setClauses) == 0 {
+		return "", nil, ErrNothingToUpdate
+	}
+
+	sql := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", table, strings.Join(setClauses, ", "), argIndex)
+	return sql, append(args, id), nil
+}
+
+func (m *MovieRepository) UpdateSchedule(ctx context.Context, body models.UpdateScheduleBody, scheduleId int) (pgconn.CommandTag, error) {
+	sql, args, err := updateSQL("schedule", body, scheduleId)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	ctag, err := m.dbpool.Exec(ctx, sql, args...)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	m.dropScheduleCaches(ctx)
+	return ctag, nil
+}
+
+// dropScheduleCaches clears the lists that depend on which schedules run:
+// the schedules and now showing lists and the upcoming lists
+func (m *MovieRepository) dropScheduleCaches(ctx context.Context) {
+	res, err := m.rdb.Del(ctx, upcomingsKeys()...).Result()
+	if err != nil {
+		log.Println(err)
+	}
+	log.Printf("Number of keys deleted: %d", res)
+	if err := utils.InvalidateCachePattern(m.rdb, ctx, "archie:schedules_*"); err != nil {
+		log.Println(err)
+	}
 }
 
 func (m *MovieRepository) sliceCinemaID(locationId int) []int {
@@ -946,13 +1980,30 @@ func (m *MovieRepository) sliceCinemaID(locationId int) []int {
 	}
 }
 
-func (m *MovieRepository) insertMovieCasts(tx pgx.Tx, ctx context.Context, movieID uint32, castCSV string) error {
-	if strings.TrimSpace(castCSV) == "" {
-		return nil
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes s match itself in a LIKE pattern, so a city typed with
+// % or _ does not match every other city
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// splitNames reads the comma separated names of the movie forms and imports
+func splitNames(csv string) []string {
+	var names []string
+	for name := range strings.SplitSeq(csv, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
 	}
+	return names
+}
 
-	castStrs := strings.SplitSeq(castCSV, ",")
-	for str := range castStrs {
+func (m *MovieRepository) insertMovieCasts(tx pgx.Tx, ctx context.Context, movieID uint32, names []string) error {
+	// the names are billed in their order, a name listed twice is credited once
+	billingOrder := 0
+	credited := map[uint32]bool{}
+	for _, str := range names {
 		str = strings.TrimSpace(str)
 		if str == "" {
 			continue
@@ -974,12 +2025,17 @@ func (m *MovieRepository) insertMovieCasts(tx pgx.Tx, ctx context.Context, movie
 		if err := tx.QueryRow(ctx, sqlC, str).Scan(&castID); err != nil {
 			return err
 		}
+		if credited[castID] {
+			continue
+		}
+		credited[castID] = true
+		billingOrder++
 
 		sqlMC := `
-            INSERT INTO movies_casts(movie_id, cast_id)
-            VALUES ($1, $2)
+            INSERT INTO movies_casts(movie_id, cast_id, billing_order)
+            VALUES ($1, $2, $3)
         `
-		if _, err := tx.Exec(ctx, sqlMC, movieID, castID); err != nil {
+		if _, err := tx.Exec(ctx, sqlMC, movieID, castID, billingOrder); err != nil {
 			return err
 		}
 	}
@@ -1037,11 +2093,15 @@ func (m *MovieRepository) insertMovieGenres(tx pgx.Tx, ctx context.Context, movi
 			continue
 		}
 
-		id, err := m.castGenre(str)
+		id, err := m.castGenre(ctx, str)
 		if err != nil {
 			// Skip unknown/invalid genre (optional: log the error)
 			continue
 		}
+		// aliases and repeats of a genre link it once
+		if slices.Contains(genreIDs, id) {
+			continue
+		}
 		genreIDs = append(genreIDs, id)
 	}
 
@@ -1092,11 +2152,14 @@ func (m *MovieRepository) insertDirectors(tx pgx.Tx, ctx context.Context, direct
 	if err := tx.QueryRow(ctx, sql, directorName).Scan(&id); err != nil {
 		return 0, err
 	}
+	if err := m.linkDirectorPerson(tx, ctx, int(id)); err != nil {
+		return 0, err
+	}
 
 	return id, nil
 }
 
-func (m *MovieRepository) GetGenres(ctx context.Context) ([]models.Genre, error) {
+func (m *MovieRepository) GetGenres(ctx context.Context, lang string) ([]models.Genre, error) {
 	sql := `
 		SELECT id, genre_name
 		FROM genres
@@ -1121,6 +2184,16 @@ func (m *MovieRepository) GetGenres(ctx context.Context) ([]models.Genre, error)
 
 		genres = append(genres, genre)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	if lang != utils.DefaultLanguage {
+		names, err := genreTranslations(ctx, m.dbpool, lang)
+		if err != nil {
+			return nil, err
+		}
+		localizeGenres(genres, names)
+	}
 	return genres, nil
 }
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// the benchmarks run against a migrated and seeded database, e.g.
+//
+//	BENCH_DB_URL=postgres://... RDB_ADDR=localhost:6379 go test -run ^$ -bench . ./internals/repositories
+//
+// queries/op must stay the same whatever movies/op is
+
+// queryCounter is a pgx tracer counting the queries sent to the database
+type queryCounter struct {
+	n atomic.Int64
+}
+
+func (q *queryCounter) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	q.n.Add(1)
+	return ctx
+}
+
+func (q *queryCounter) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+func benchMovieRepository(b *testing.B) (*MovieRepository, *queryCounter) {
+	b.Helper()
+	dbURL := os.Getenv("BENCH_DB_URL")
+	if dbURL == "" {
+		b.Skip("BENCH_DB_URL is not set")
+	}
+	ctx := context.Background()
+
+	cfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		b.Fatal(err)
+	}
+	counter := &queryCounter{}
+	cfg.ConnConfig.Tracer = counter
+	dbpool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(dbpool.Close)
+
+	rdb := redis.NewClient(&redis.Options{Addr: os.Getenv("RDB_ADDR")})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		b.Skipf("redis is not reachable: %v", err)
+	}
+	b.Cleanup(func() { rdb.Close() })
+
+	return NewMovieRepository(dbpool, rdb), counter
+}
+
+// startCounting resets the counter and the timer once setup queries are done
+func startCounting(b *testing.B, counter *queryCounter) {
+	counter.n.Store(0)
+	b.ResetTimer()
+}
+
+func reportQueries(b *testing.B, counter *queryCounter, movies int) {
+	b.ReportMetric(float64(counter.n.Load())/float64(b.N), "queries/op")
+	b.ReportMetric(float64(movies), "movies/op")
+}
+
+func BenchmarkGetMovieWithGenrePageSearch(b *testing.B) {
+	mr, counter := benchMovieRepository(b)
+	ctx := context.Background()
+
+	for _, limit := range []int{10, 50, 100} {
+		b.Run(fmt.Sprintf("limit=%d", limit), func(b *testing.B) {
+			var movies []models.MovieFilter
+			startCounting(b, counter)
+			for range b.N {
+				var err error
+				movies, _, err = mr.GetMovieWithGenrePageSearch(ctx, models.MovieQuery{}, limit, 0, utils.DefaultLanguage)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			reportQueries(b, counter, len(movies))
+		})
+	}
+}
+
+func BenchmarkGetAllMovies(b *testing.B) {
+	mr, counter := benchMovieRepository(b)
+	ctx := context.Background()
+
+	var movies []models.MovieFilter
+	startCounting(b, counter)
+	for range b.N {
+		var err error
+		movies, err = mr.GetAllMovies(ctx, "")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportQueries(b, counter, len(movies))
+}
+
+func BenchmarkGetPopularMovies(b *testing.B) {
+	mr, counter := benchMovieRepository(b)
+	ctx := context.Background()
+	redisKey := fmt.Sprintf("archie:movies_populars_%d_%s", PopularityDefaultWindow, utils.DefaultLanguage)
+
+	var movies []models.MovieFilter
+	startCounting(b, counter)
+	for range b.N {
+		// every run must miss the cache
+		b.StopTimer()
+		if err := mr.rdb.Del(ctx, redisKey).Err(); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		var err error
+		movies, err = mr.GetPopularMovies(ctx, PopularityDefaultWindow, utils.DefaultLanguage)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportQueries(b, counter, len(movies))
+}
+
+func BenchmarkGetMovieDetail(b *testing.B) {
+	mr, counter := benchMovieRepository(b)
+	ctx := context.Background()
+
+	// the movie with the most casts and genres
+	var movieId int
+	if err := mr.dbpool.QueryRow(ctx, `
+		SELECT m.id
+		FROM movies m
+		WHERE m.deleted_at IS NULL
+		ORDER BY
+			(SELECT COUNT(*) FROM movies_casts mc WHERE mc.movie_id = m.id)
+			+ (SELECT COUNT(*) FROM movies_genres mg WHERE mg.movie_id = m.id) DESC
+		LIMIT 1
+	`).Scan(&movieId); err != nil {
+		b.Skipf("no movie seeded: %v", err)
+	}
+
+	startCounting(b, counter)
+	for range b.N {
+		if _, err := mr.GetMovieDetail(ctx, movieId, utils.DefaultLanguage); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportQueries(b, counter, 1)
+}
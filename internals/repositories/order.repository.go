@@ -2,20 +2,43 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/redis/go-redis/v9"
 )
 
+var (
+	ErrScheduleNotFound  = errors.New("schedule not found")
+	ErrScheduleStarted   = errors.New("schedule already started")
+	ErrScheduleCancelled = errors.New("schedule was cancelled")
+	ErrUnderAge          = errors.New("buyer is under the age rating of the movie")
+	ErrBirthdateRequired = errors.New("birthdate is required to book an age rated movie")
+	ErrBuyerNotFound     = errors.New("user not found")
+)
+
+// minimum age of each LSF rating
+var ageRatingMinAge = map[string]int{
+	"SU":  0,
+	"13+": 13,
+	"17+": 17,
+	"21+": 21,
+}
+
 type OrderRepository struct {
 	dbpool *pgxpool.Pool
+	rdb    *redis.Client
 }
 
-func NewOrderRepository(dbpool *pgxpool.Pool) *OrderRepository {
-	return &OrderRepository{dbpool: dbpool}
+func NewOrderRepository(dbpool *pgxpool.Pool, rdb *redis.Client) *OrderRepository {
+	return &OrderRepository{dbpool: dbpool, rdb: rdb}
 }
 
 func (o *OrderRepository) GetOrderHistories(ctx context.Context) ([]models.OrderHistory, error) {
@@ -86,36 +109,78 @@ func (o *OrderRepository) getOrderSeats(ctx context.Context, bookId int) ([]stri
 }
 
 func (o *OrderRepository) CreateOrder(ctx context.Context, body models.CinemaOrderBody, uid uint16, seats ...int) (string, error) {
+	return o.createOrder(ctx, body, uid, nil)
+}
+
+// CreateBoxOfficeOrder books on behalf of uid, with override an admin takes
+// responsibility for a buyer who fails the age check and is recorded on the order
+func (o *OrderRepository) CreateBoxOfficeOrder(ctx context.Context, body models.CinemaOrderBody, uid, adminId uint16, override bool) (string, error) {
+	// an unknown buyer would otherwise fail the age check or the insert
+	var isUser bool
+	if err := o.dbpool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)", uid,
+	).Scan(&isUser); err != nil {
+		return "", err
+	}
+	if !isUser {
+		return "", ErrBuyerNotFound
+	}
+
+	if !override {
+		return o.createOrder(ctx, body, uid, nil)
+	}
+	return o.createOrder(ctx, body, uid, &adminId)
+}
+
+func (o *OrderRepository) createOrder(ctx context.Context, body models.CinemaOrderBody, uid uint16, overriddenBy *uint16) (string, error) {
 	tx, err := o.dbpool.Begin(ctx)
 	if err != nil {
 		return "", err
 	}
 	defer tx.Rollback(ctx)
 
+	if err := o.checkScheduleOpen(tx, ctx, int(body.ScheduleID)); err != nil {
+		return "", err
+	}
+
+	if err := o.checkBuyerAge(tx, ctx, int(body.ScheduleID), uid); err != nil {
+		if overriddenBy == nil || !(errors.Is(err, ErrUnderAge) || errors.Is(err, ErrBirthdateRequired)) {
+			return "", err
+		}
+		log.Printf("age check of user %d overridden by admin %d: %v", uid, *overriddenBy, err)
+	} else {
+		// nothing was overridden
+		overriddenBy = nil
+	}
+
 	var sql string
 	if body.PaidAt == nil {
 		sql = `
-		INSERT INTO orders (user_id, schedule_id, payment_method, total)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO orders (user_id, schedule_id, payment_method, total, age_check_overridden_by)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id
 	`
 	} else if *body.PaidAt {
 		sql = `
-		INSERT INTO orders (user_id, schedule_id, payment_method, total, paid_at)
-		VALUES ($1, $2, $3, $4, NOW())
+		INSERT INTO orders (user_id, schedule_id, payment_method, total, age_check_overridden_by, paid_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
 		RETURNING id
 	`
 	} else {
 		// explicitly unpaid (false)
 		sql = `
-		INSERT INTO orders (user_id, schedule_id, payment_method, total, paid_at)
-		VALUES ($1, $2, $3, $4, NULL)
+		INSERT INTO orders (user_id, schedule_id, payment_method, total, age_check_overridden_by, paid_at)
+		VALUES ($1, $2, $3, $4, $5, NULL)
 		RETURNING id
 	`
 	}
 
 	var orderId int
-	if err := tx.QueryRow(ctx, sql, uid, body.ScheduleID, body.PaymentMethod, body.Total).Scan(&orderId); err != nil {
+	if err := tx.QueryRow(ctx, sql, uid, body.ScheduleID, body.PaymentMethod, body.Total, overriddenBy).Scan(&orderId); err != nil {
+		// the schedule is checked above, the buyer may be deleted meanwhile
+		if utils.IsPgError(err, utils.PgForeignKeyViolation) {
+			return "", ErrBuyerNotFound
+		}
 		return "", err
 	}
 
@@ -124,12 +189,101 @@ func (o *OrderRepository) CreateOrder(ctx context.Context, body models.CinemaOrd
 		if err := tx.Commit(ctx); err != nil {
 			return "", err
 		}
+		// a new booking changes what the user is recommended
+		if err := utils.InvalidateCachePattern(o.rdb, ctx, recommendationsKey(uid, "*")); err != nil {
+			log.Println(err)
+		}
+		if body.PaidAt != nil && *body.PaidAt {
+			if err := o.recordOrderSales(ctx, int(body.ScheduleID), len(body.Seats)); err != nil {
+				log.Println(err)
+			}
+		}
 		return fmt.Sprintf("%s: CREATE ORDER", ctag.String()), nil
 	}
 
 	return "", err
 }
 
+// recordOrderSales counts the tickets of a paid order towards the popularity
+// of the scheduled movie
+func (o *OrderRepository) recordOrderSales(ctx context.Context, scheduleId, tickets int) error {
+	var movieId int
+	if err := o.dbpool.QueryRow(ctx,
+		"SELECT movie_id FROM schedule WHERE id = $1", scheduleId,
+	).Scan(&movieId); err != nil {
+		return err
+	}
+	return recordTicketSales(ctx, o.rdb, movieId, tickets, time.Now())
+}
+
+// checkScheduleOpen rejects bookings once a schedule has started, compared
+// as absolute time in the timezone of its location
+func (o *OrderRepository) checkScheduleOpen(tx pgx.Tx, ctx context.Context, scheduleId int) error {
+	sql := `
+		SELECT ` + upcomingScheduleCond + `, ` + liveScheduleCond + `
+		FROM schedule s
+		JOIN movies m ON m.id = s.movie_id
+		JOIN jam_tayang t ON t.id = s.time_id
+		JOIN lokasi_tayang l ON l.id = s.location_id
+		WHERE s.id = $1
+	`
+	var isOpen, isLive bool
+	if err := tx.QueryRow(ctx, sql, scheduleId).Scan(&isOpen, &isLive); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrScheduleNotFound
+		}
+		return err
+	}
+	if !isLive {
+		return ErrScheduleCancelled
+	}
+	if !isOpen {
+		return ErrScheduleStarted
+	}
+
+	return nil
+}
+
+// checkBuyerAge compares the age of the buyer on the show date with the LSF
+// rating of the scheduled movie
+func (o *OrderRepository) checkBuyerAge(tx pgx.Tx, ctx context.Context, scheduleId int, uid uint16) error {
+	sql := `
+		SELECT
+			m.age_rating,
+			EXTRACT(YEAR FROM AGE(s.show_date, p.birthdate))::int
+		FROM
+			schedule s
+		JOIN
+			movies m ON m.id = s.movie_id
+		LEFT JOIN
+			personal_info p ON p.user_id = $2
+		WHERE
+			s.id = $1
+	`
+	var (
+		ageRating string
+		age       *int
+	)
+	if err := tx.QueryRow(ctx, sql, scheduleId, uid).Scan(&ageRating, &age); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrScheduleNotFound
+		}
+		return err
+	}
+
+	minAge := ageRatingMinAge[ageRating]
+	if minAge == 0 {
+		return nil
+	}
+	if age == nil {
+		return fmt.Errorf("%w, the movie is rated %s", ErrBirthdateRequired, ageRating)
+	}
+	if *age < minAge {
+		return fmt.Errorf("%w, the movie is rated %s", ErrUnderAge, ageRating)
+	}
+	return nil
+}
+
 func (o *OrderRepository) createBookSeats(tx pgx.Tx, ctx context.Context, orderId int, seats []int) (pgconn.CommandTag, error) {
 	sql := `
 		INSERT INTO
@@ -0,0 +1,470 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+var (
+	ErrPersonNotFound = errors.New("person not found")
+	ErrPersonInUse    = errors.New("person is still credited, merge them into another instead")
+	ErrDuplicateName  = errors.New("name is already taken")
+)
+
+// casts holds every person, a director links to the person they are through
+// person_id. peopleTables maps each kind of person to its table and the count
+// of movies crediting them as that kind, p is the row of the table
+var peopleTables = map[string]struct{ table, credits string }{
+	"director": {"directors", "SELECT COUNT(*) FROM movies m WHERE m.director_id = p.id"},
+	"cast": {"casts", `SELECT COUNT(*) FROM (
+		SELECT mc.movie_id FROM movies_casts mc WHERE mc.cast_id = p.id
+		UNION
+		SELECT cr.movie_id FROM movie_crew cr WHERE cr.person_id = p.id
+	) credited`},
+}
+
+// linkDirectorPerson links a director to the person of the same name, the
+// person is added when there is none yet
+func (m *MovieRepository) linkDirectorPerson(tx pgx.Tx, ctx context.Context, directorId int) error {
+	sql := `
+		WITH person AS (
+			INSERT INTO casts (name)
+			SELECT name FROM directors WHERE id = $1 AND person_id IS NULL
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		)
+		UPDATE directors SET person_id = (SELECT id FROM person)
+		WHERE id = $1 AND person_id IS NULL
+	`
+	_, err := tx.Exec(ctx, sql, directorId)
+	return err
+}
+
+// linkedPeople returns the ids of kind together with the directors and
+// persons they are linked to, the same person under both kinds
+func (m *MovieRepository) linkedPeople(tx pgx.Tx, ctx context.Context, kind string, ids []int) (map[string][]int, error) {
+	sql := `
+		SELECT 'director', id FROM directors WHERE id = ANY($1)
+		UNION
+		SELECT 'cast', person_id FROM directors WHERE id = ANY($1) AND person_id IS NOT NULL
+	`
+	if kind == "cast" {
+		sql = `
+			SELECT 'cast', id FROM casts WHERE id = ANY($1)
+			UNION
+			SELECT 'director', id FROM directors WHERE person_id = ANY($1)
+		`
+	}
+	rows, err := tx.Query(ctx, sql, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	people := map[string][]int{}
+	for rows.Next() {
+		var (
+			linkedKind string
+			id         int
+		)
+		if err := rows.Scan(&linkedKind, &id); err != nil {
+			return nil, err
+		}
+		people[linkedKind] = append(people[linkedKind], id)
+	}
+	return people, rows.Err()
+}
+
+// refreshPeopleMovies rebuilds the search document of every movie crediting
+// people, their names are part of it
+func (m *MovieRepository) refreshPeopleMovies(tx pgx.Tx, ctx context.Context, people map[string][]int) error {
+	sql := `
+		SELECT id FROM movies WHERE director_id = ANY($1)
+		UNION
+		SELECT movie_id FROM movies_casts WHERE cast_id = ANY($2)
+	`
+	rows, err := tx.Query(ctx, sql, people["director"], people["cast"])
+	if err != nil {
+		return err
+	}
+	movieIds, err := pgx.CollectRows(rows, pgx.RowTo[uint32])
+	if err != nil {
+		return err
+	}
+
+	for _, movieId := range movieIds {
+		if err := m.refreshSearchDocument(tx, ctx, movieId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterPeopleChange re-indexes the suggestions of people and drops the
+// cached lists carrying their names
+func (m *MovieRepository) afterPeopleChange(ctx context.Context, people map[string][]int) {
+	if err := m.reindexSuggestions(ctx, people); err != nil {
+		log.Println(err)
+	}
+	m.bustMovieListCaches(ctx)
+}
+
+func (m *MovieRepository) GetPeople(ctx context.Context, kind, q string, limit, offset int) ([]models.PersonSummary, int, error) {
+	source := peopleTables[kind]
+
+	var total int
+	if err := m.dbpool.QueryRow(ctx,
+		fmt.Sprintf("SELECT COUNT(*) FROM %s p WHERE $1 = '' OR p.name ILIKE '%%' || $1 || '%%'", source.table), q,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// duplicates sort next to each other
+	sql := fmt.Sprintf(`
+		SELECT p.id, p.name, (%s)
+		FROM %s p
+		WHERE $1 = '' OR p.name ILIKE '%%' || $1 || '%%'
+		ORDER BY LOWER(p.name) ASC, p.id ASC
+		LIMIT $2 OFFSET $3
+	`, source.credits, source.table)
+	rows, err := m.dbpool.Query(ctx, sql, q, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	people := []models.PersonSummary{}
+	for rows.Next() {
+		var person models.PersonSummary
+		if err := rows.Scan(&person.ID, &person.Name, &person.MovieCount); err != nil {
+			return nil, 0, err
+		}
+		people = append(people, person)
+	}
+	return people, total, rows.Err()
+}
+
+func (m *MovieRepository) CreatePerson(ctx context.Context, kind, name string) (int, error) {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	if err := tx.QueryRow(ctx,
+		fmt.Sprintf("INSERT INTO %s (name) VALUES ($1) RETURNING id", peopleTables[kind].table), name,
+	).Scan(&id); err != nil {
+		if utils.IsPgError(err, utils.PgUniqueViolation) {
+			return 0, ErrDuplicateName
+		}
+		return 0, err
+	}
+	if kind == "director" {
+		if err := m.linkDirectorPerson(tx, ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, tx.Commit(ctx)
+}
+
+// RenamePerson renames a director or cast member, the same person under the
+// other kind is renamed with them
+func (m *MovieRepository) RenamePerson(ctx context.Context, kind string, id int, name string) error {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	people, err := m.linkedPeople(tx, ctx, kind, []int{id})
+	if err != nil {
+		return err
+	}
+	if len(people[kind]) == 0 {
+		return ErrPersonNotFound
+	}
+
+	for linkedKind, ids := range people {
+		if _, err := tx.Exec(ctx,
+			fmt.Sprintf("UPDATE %s SET name = $1 WHERE id = ANY($2)", peopleTables[linkedKind].table), name, ids,
+		); err != nil {
+			if utils.IsPgError(err, utils.PgUniqueViolation) {
+				return ErrDuplicateName
+			}
+			return err
+		}
+	}
+	if err := m.refreshPeopleMovies(tx, ctx, people); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	m.afterPeopleChange(ctx, people)
+	return nil
+}
+
+// DeletePerson removes a director or cast member nobody credits, duplicates
+// still credited are merged instead
+func (m *MovieRepository) DeletePerson(ctx context.Context, kind string, id int) error {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	source := peopleTables[kind]
+	inUse := "(" + source.credits + ") > 0"
+	if kind == "cast" {
+		// a director's person goes with the director
+		inUse += " OR EXISTS (SELECT 1 FROM directors d WHERE d.person_id = p.id)"
+	}
+	var isCredited bool
+	if err := tx.QueryRow(ctx,
+		fmt.Sprintf("SELECT %s FROM %s p WHERE p.id = $1", inUse, source.table), id,
+	).Scan(&isCredited); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPersonNotFound
+		}
+		return err
+	}
+	if isCredited {
+		return ErrPersonInUse
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", source.table), id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	if err := m.removeSuggestion(ctx, kind, id); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+// MergePeople moves every credit of ids to targetId and removes ids, for
+// duplicates like "Tom Hanks" and "tom hanks"
+func (m *MovieRepository) MergePeople(ctx context.Context, kind string, targetId int, ids []int) error {
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	people, err := m.linkedPeople(tx, ctx, kind, append([]int{targetId}, ids...))
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(people[kind], targetId) {
+		return ErrPersonNotFound
+	}
+
+	if kind == "director" {
+		if _, err := tx.Exec(ctx,
+			"UPDATE movies SET director_id = $1 WHERE director_id = ANY($2)", targetId, ids,
+		); err != nil {
+			return err
+		}
+		if err := m.linkDirectorPerson(tx, ctx, targetId); err != nil {
+			return err
+		}
+		// the crew directing credits of their persons move to the person of targetId
+		if err := m.mergeCrew(tx, ctx, "director", `
+			SELECT person_id FROM directors WHERE id = $1
+		`, `
+			SELECT person_id FROM directors WHERE id = ANY($2) AND person_id IS NOT NULL
+		`, targetId, ids); err != nil {
+			return err
+		}
+	} else {
+		// a movie crediting several of them keeps one credit
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM movies_casts mc
+			WHERE mc.cast_id = ANY($2) AND EXISTS (
+				SELECT 1 FROM movies_casts o
+				WHERE o.movie_id = mc.movie_id
+				AND (o.cast_id = $1 OR (o.cast_id = ANY($2) AND o.cast_id < mc.cast_id))
+			)
+		`, targetId, ids); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			"UPDATE movies_casts SET cast_id = $1 WHERE cast_id = ANY($2)", targetId, ids,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			"UPDATE directors SET person_id = $1 WHERE person_id = ANY($2)", targetId, ids,
+		); err != nil {
+			return err
+		}
+		if err := m.mergeCrew(tx, ctx, "", "SELECT $1::INT", "SELECT UNNEST($2::INT[])", targetId, ids); err != nil {
+			return err
+		}
+	}
+
+	ctag, err := tx.Exec(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE id = ANY($1)", peopleTables[kind].table), ids,
+	)
+	if err != nil {
+		return err
+	}
+	if ctag.RowsAffected() != int64(len(ids)) {
+		return fmt.Errorf("%w: every id must be an existing %s", ErrPersonNotFound, kind)
+	}
+
+	// the movies of the merged people credit targetId now
+	if err := m.refreshPeopleMovies(tx, ctx, map[string][]int{kind: {targetId}}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	m.afterPeopleChange(ctx, people)
+	return nil
+}
+
+// mergeCrew moves the crew credits of the persons selected by sources to the
+// person selected by target, of job only unless job is empty. a movie
+// crediting several of them for a job keeps one credit
+func (m *MovieRepository) mergeCrew(tx pgx.Tx, ctx context.Context, job, target, sources string, targetId int, ids []int) error {
+	sql := fmt.Sprintf(`
+		WITH target AS (%s), sources AS (%s)
+		DELETE FROM movie_crew cr
+		WHERE cr.person_id IN (SELECT * FROM sources)
+		AND cr.person_id NOT IN (SELECT * FROM target)
+		AND ($3 = '' OR cr.job = $3)
+		AND EXISTS (
+			SELECT 1 FROM movie_crew o
+			WHERE o.movie_id = cr.movie_id AND o.job = cr.job
+			AND (
+				o.person_id IN (SELECT * FROM target)
+				OR (o.person_id IN (SELECT * FROM sources) AND o.person_id < cr.person_id)
+			)
+		)
+	`, target, sources)
+	if _, err := tx.Exec(ctx, sql, targetId, ids, job); err != nil {
+		return err
+	}
+
+	sql = fmt.Sprintf(`
+		WITH target AS (%s), sources AS (%s)
+		UPDATE movie_crew SET person_id = (SELECT * FROM target)
+		WHERE person_id IN (SELECT * FROM sources)
+		AND ($3 = '' OR job = $3)
+	`, target, sources)
+	_, err := tx.Exec(ctx, sql, targetId, ids, job)
+	return err
+}
+
+// GetPerson returns a person with the live movies they acted in, directed
+// and crewed, newest first
+func (m *MovieRepository) GetPerson(ctx context.Context, id int, lang string) (models.Person, error) {
+	person := models.Person{
+		Acted:    []models.PersonCredit{},
+		Directed: []models.PersonCredit{},
+		Crew:     []models.PersonCredit{},
+	}
+	if err := m.dbpool.QueryRow(ctx,
+		"SELECT id, name FROM casts WHERE id = $1", id,
+	).Scan(&person.ID, &person.Name); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Person{}, ErrPersonNotFound
+		}
+		return models.Person{}, err
+	}
+
+	sql := `
+		SELECT
+			'acted', m.id, m.title, m.poster_path, m.release_date, m.age_rating, m.rating_avg, m.rating_count,
+			mc.character_name, ''
+		FROM
+			movies_casts mc
+		JOIN
+			movies m ON m.id = mc.movie_id
+		WHERE
+			mc.cast_id = $1 AND m.deleted_at IS NULL
+		UNION ALL
+		SELECT
+			CASE WHEN cr.job = 'director' THEN 'directed' ELSE 'crew' END,
+			m.id, m.title, m.poster_path, m.release_date, m.age_rating, m.rating_avg, m.rating_count,
+			NULL, CASE WHEN cr.job = 'director' THEN '' ELSE cr.job END
+		FROM
+			movie_crew cr
+		JOIN
+			movies m ON m.id = cr.movie_id
+		WHERE
+			cr.person_id = $1 AND m.deleted_at IS NULL
+		ORDER BY
+			release_date DESC, id ASC
+	`
+	rows, err := m.dbpool.Query(ctx, sql, id)
+	if err != nil {
+		return models.Person{}, err
+	}
+	defer rows.Close()
+
+	var (
+		roles   []string
+		credits []models.PersonCredit
+	)
+	for rows.Next() {
+		var (
+			role   string
+			credit models.PersonCredit
+		)
+		if err := rows.Scan(
+			&role,
+			&credit.ID,
+			&credit.Title,
+			&credit.PosterPath,
+			&credit.ReleaseDate,
+			&credit.AgeRating,
+			&credit.Rating.Average,
+			&credit.Rating.Count,
+			&credit.Character,
+			&credit.Job,
+		); err != nil {
+			return models.Person{}, err
+		}
+		roles = append(roles, role)
+		credits = append(credits, credit)
+	}
+	if err := rows.Err(); err != nil {
+		return models.Person{}, err
+	}
+
+	if err := localizeMovies(ctx, m.dbpool, lang, credits, func(credit *models.PersonCredit) localized {
+		return localized{id: credit.ID, title: &credit.Title}
+	}); err != nil {
+		return models.Person{}, err
+	}
+	for i, credit := range credits {
+		switch roles[i] {
+		case "directed":
+			person.Directed = append(person.Directed, credit)
+		case "crew":
+			person.Crew = append(person.Crew, credit)
+		default:
+			person.Acted = append(person.Acted, credit)
+		}
+	}
+	return person, nil
+}
@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tickets sold are counted in one sorted set per UTC day, members are movie
+// ids. a window score sums the days of the window, each halved for every
+// popularityHalfLife days it lies in the past, so recent sales outrank old ones
+const (
+	popularityBucketKey     = "archie:popularity:"
+	popularityHalfLife      = 2.0
+	PopularityDefaultWindow = 7
+	PopularityMaxWindow     = 30
+	popularLimit            = 20
+)
+
+func popularityBucket(day time.Time) string {
+	return popularityBucketKey + day.UTC().Format("2006-01-02")
+}
+
+// recordTicketSales adds the tickets of a paid order to the bucket of its day
+func recordTicketSales(ctx context.Context, rdb *redis.Client, movieId, tickets int, paidAt time.Time) error {
+	key := popularityBucket(paidAt)
+
+	pipe := rdb.TxPipeline()
+	pipe.ZIncrBy(ctx, key, float64(tickets), strconv.Itoa(movieId))
+	pipe.Expire(ctx, key, (PopularityMaxWindow+1)*24*time.Hour)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// decayWeight is what a ticket sold age days ago counts for today
+func decayWeight(age int) float64 {
+	return math.Pow(0.5, float64(age)/popularityHalfLife)
+}
+
+// windowedPopularity returns the decayed ticket sales of every movie sold in
+// the last days, today included
+func windowedPopularity(ctx context.Context, rdb *redis.Client, days int) (map[int]float64, error) {
+	now := time.Now()
+
+	pipe := rdb.Pipeline()
+	cmds := make([]*redis.ZSliceCmd, days)
+	for age := range days {
+		cmds[age] = pipe.ZRangeWithScores(ctx, popularityBucket(now.AddDate(0, 0, -age)), 0, -1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	scores := map[int]float64{}
+	for age, cmd := range cmds {
+		weight := decayWeight(age)
+		for _, z := range cmd.Val() {
+			movieId, err := strconv.Atoi(z.Member.(string))
+			if err != nil {
+				continue
+			}
+			scores[movieId] += z.Score * weight
+		}
+	}
+	return scores, nil
+}
+
+// splitScores turns scores into the id and score arrays UNNEST joins on
+func splitScores(scores map[int]float64) ([]int, []float64) {
+	movieIds := make([]int, 0, len(scores))
+	movieScores := make([]float64, 0, len(scores))
+	for id, score := range scores {
+		movieIds = append(movieIds, id)
+		movieScores = append(movieScores, score)
+	}
+	return movieIds, movieScores
+}
+
+// ticketSale is the tickets of a movie paid on a UTC day
+type ticketSale struct {
+	movieId, tickets int
+	day              time.Time
+}
+
+// ticketSalesSQL groups the tickets paid in the longest window by movie and
+// day, %s narrows the schedules counted
+const ticketSalesSQL = `
+	SELECT
+		s.movie_id, (o.paid_at AT TIME ZONE 'UTC')::date, COUNT(os.seat_id)
+	FROM
+		orders o
+	JOIN
+		schedule s ON s.id = o.schedule_id
+	JOIN
+		orders_seats os ON os.order_id = o.id
+	WHERE
+		o.paid_at >= NOW() - make_interval(days => $1) AND %s
+	GROUP BY
+		s.movie_id, (o.paid_at AT TIME ZONE 'UTC')::date
+`
+
+func (m *MovieRepository) ticketSales(ctx context.Context, cond string, args ...any) ([]ticketSale, error) {
+	rows, err := m.dbpool.Query(ctx, fmt.Sprintf(ticketSalesSQL, cond), append([]any{PopularityMaxWindow}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []ticketSale
+	for rows.Next() {
+		var s ticketSale
+		if err := rows.Scan(&s.movieId, &s.day, &s.tickets); err != nil {
+			return nil, err
+		}
+		sales = append(sales, s)
+	}
+	return sales, rows.Err()
+}
+
+// RebuildPopularity refills the daily buckets from the paid orders of live
+// schedules in the longest window. it is run on startup before serving so
+// redis can be flushed safely, the buckets are built aside and renamed over
+// the live ones at once
+func (m *MovieRepository) RebuildPopularity(ctx context.Context) error {
+	sales, err := m.ticketSales(ctx, "s.cancelled_at IS NULL")
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	iter := m.rdb.Scan(ctx, 0, popularityBucketKey+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		stale = append(stale, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	rebuilt := map[string]time.Time{}
+	build := m.rdb.Pipeline()
+	for _, s := range sales {
+		key := popularityBucket(s.day)
+		if _, ok := rebuilt[key]; !ok {
+			// a rebuild cut short may have left its buckets behind
+			build.Del(ctx, key+":rebuild")
+			rebuilt[key] = s.day
+		}
+		build.ZAdd(ctx, key+":rebuild", redis.Z{Score: float64(s.tickets), Member: strconv.Itoa(s.movieId)})
+	}
+	if _, err := build.Exec(ctx); err != nil {
+		return err
+	}
+
+	swap := m.rdb.TxPipeline()
+	for _, key := range stale {
+		if _, ok := rebuilt[strings.TrimSuffix(key, ":rebuild")]; !ok {
+			swap.Del(ctx, key)
+		}
+	}
+	for key, day := range rebuilt {
+		swap.Rename(ctx, key+":rebuild", key)
+		swap.ExpireAt(ctx, key, day.AddDate(0, 0, PopularityMaxWindow+1))
+	}
+	_, err = swap.Exec(ctx)
+	return err
+}
+
+// dropScheduleSales takes the tickets paid for cancelled schedules back out of
+// the buckets they were counted in
+func (m *MovieRepository) dropScheduleSales(ctx context.Context, scheduleIds []int) error {
+	if len(scheduleIds) == 0 {
+		return nil
+	}
+	sales, err := m.ticketSales(ctx, "s.id = ANY($2)", scheduleIds)
+	if err != nil {
+		return err
+	}
+
+	pipe := m.rdb.TxPipeline()
+	for _, s := range sales {
+		key := popularityBucket(s.day)
+		pipe.ZIncrBy(ctx, key, -float64(s.tickets), strconv.Itoa(s.movieId))
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "0")
+		pipe.ExpireAt(ctx, key, s.day.AddDate(0, 0, PopularityMaxWindow+1))
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
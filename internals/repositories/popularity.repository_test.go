@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDecayWeight(t *testing.T) {
+	tests := []struct {
+		age  int
+		want float64
+	}{
+		{0, 1},
+		{1, math.Sqrt2 / 2},
+		{2, 0.5},
+		{4, 0.25},
+		{6, 0.125},
+	}
+	for _, tt := range tests {
+		if got := decayWeight(tt.age); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("decayWeight(%d) = %v, want %v", tt.age, got, tt.want)
+		}
+	}
+
+	for age := 1; age < PopularityMaxWindow; age++ {
+		if decayWeight(age) >= decayWeight(age-1) {
+			t.Fatalf("a sale %d days old counts as much as one a day younger", age)
+		}
+	}
+}
+
+func TestPopularityBucketIsUTCDay(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	// 05:00 in Jakarta is still the day before in UTC
+	paidAt := time.Date(2025, 3, 2, 5, 0, 0, 0, jakarta)
+	if got, want := popularityBucket(paidAt), popularityBucketKey+"2025-03-01"; got != want {
+		t.Errorf("popularityBucket = %q, want %q", got, want)
+	}
+}
+
+func TestSplitScores(t *testing.T) {
+	scores := map[int]float64{3: 1.5, 7: 4, 9: 0.25}
+	ids, values := splitScores(scores)
+	if len(ids) != len(scores) || len(values) != len(scores) {
+		t.Fatalf("got %d ids and %d scores, want %d", len(ids), len(values), len(scores))
+	}
+	for i, id := range ids {
+		if scores[id] != values[i] {
+			t.Errorf("movie %d paired with %v, want %v", id, values[i], scores[id])
+		}
+	}
+}
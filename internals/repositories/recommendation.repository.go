@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// a booked movie's director counts more than one of its casts, and a cast
+// more than one of its genres. the affinity to those is blended with the
+// recent ticket sales so users without orders still get a list
+const (
+	recommendGenreWeight    = 1.0
+	recommendDirectorWeight = 3.0
+	recommendCastWeight     = 2.0
+	recommendAffinityShare  = 0.7
+	recommendPopularityDays = 14
+	recommendLimit          = 50
+	recommendTTL            = 10 * time.Minute
+)
+
+// recommendationsKey is the cached list of a user in lang, "*" matches
+// every language
+func recommendationsKey(userId uint16, lang string) string {
+	return fmt.Sprintf("archie:recommendations_%d_%s", userId, lang)
+}
+
+type RecommendationRepository struct {
+	dbpool *pgxpool.Pool
+	rdb    *redis.Client
+}
+
+func NewRecommendationRepository(dbpool *pgxpool.Pool, rdb *redis.Client) *RecommendationRepository {
+	return &RecommendationRepository{dbpool: dbpool, rdb: rdb}
+}
+
+// GetRecommendations ranks the movies with an upcoming showtime the user has
+// not booked yet, best first. any order of a schedule that still runs
+// counts as booked, paid or not
+func (r *RecommendationRepository) GetRecommendations(ctx context.Context, userId uint16, lang string) ([]models.Recommendation, error) {
+	redisKey := recommendationsKey(userId, lang)
+	var cached []models.Recommendation
+
+	isExist, err := utils.CacheGet(r.rdb, ctx, redisKey, &cached)
+	if err != nil {
+		utils.PrintError("redis> REDIS ERROR", 20, err)
+	}
+	if isExist {
+		return cached, nil
+	}
+
+	scores, err := windowedPopularity(ctx, r.rdb, recommendPopularityDays)
+	if err != nil {
+		return nil, err
+	}
+	movieIds, movieScores := splitScores(scores)
+
+	sql := `
+		WITH booked AS (
+			SELECT DISTINCT s.movie_id
+			FROM orders o
+			JOIN schedule s ON s.id = o.schedule_id
+			WHERE o.user_id = $1 AND s.cancelled_at IS NULL
+		),
+		genre_pref AS (
+			SELECT mg.genre_id, COUNT(*) AS w
+			FROM booked b
+			JOIN movies_genres mg ON mg.movie_id = b.movie_id
+			GROUP BY mg.genre_id
+		),
+		director_pref AS (
+			SELECT m.director_id, COUNT(*) AS w
+			FROM booked b
+			JOIN movies m ON m.id = b.movie_id
+			WHERE m.director_id IS NOT NULL
+			GROUP BY m.director_id
+		),
+		cast_pref AS (
+			SELECT mc.cast_id, COUNT(*) AS w
+			FROM booked b
+			JOIN movies_casts mc ON mc.movie_id = b.movie_id
+			GROUP BY mc.cast_id
+		),
+		scored AS (
+			SELECT
+				m.id, m.title, m.poster_path, m.release_date, m.rating_avg, m.rating_count, m.age_rating,
+				COALESCE(p.score, 0) AS popularity,
+				$2::float8 * COALESCE((
+					SELECT SUM(gp.w) FROM movies_genres mg JOIN genre_pref gp ON gp.genre_id = mg.genre_id
+					WHERE mg.movie_id = m.id
+				), 0)
+				+ $3::float8 * COALESCE((
+					SELECT dp.w FROM director_pref dp WHERE dp.director_id = m.director_id
+				), 0)
+				+ $4::float8 * COALESCE((
+					SELECT SUM(cp.w) FROM movies_casts mc JOIN cast_pref cp ON cp.cast_id = mc.cast_id
+					WHERE mc.movie_id = m.id
+				), 0) AS affinity
+			FROM
+				movies m
+			LEFT JOIN
+				UNNEST($7::int[], $8::float8[]) AS p(movie_id, score) ON p.movie_id = m.id
+			WHERE
+				m.deleted_at IS NULL
+			AND
+				m.id NOT IN (SELECT movie_id FROM booked)
+			AND EXISTS (
+				SELECT 1
+				FROM schedule s
+				JOIN jam_tayang t ON t.id = s.time_id
+				JOIN lokasi_tayang l ON l.id = s.location_id
+				WHERE s.movie_id = m.id AND ` + liveScheduleCond + ` AND ` + upcomingScheduleCond + `
+			)
+		),
+		normalized AS (
+			SELECT
+				*,
+				COALESCE(affinity / NULLIF(MAX(affinity) OVER (), 0), 0) AS affinity_norm,
+				COALESCE(popularity / NULLIF(MAX(popularity) OVER (), 0), 0) AS popularity_norm
+			FROM scored
+		)
+		SELECT
+			m.id, m.title, m.poster_path, m.release_date, ` + movieGenresJSON + `,
+			m.rating_avg, m.rating_count, m.age_rating, m.affinity_norm,
+			$5::float8 * m.affinity_norm + (1 - $5::float8) * m.popularity_norm AS score
+		FROM
+			normalized m
+		ORDER BY
+			score DESC, m.release_date DESC, m.id ASC
+		LIMIT $6
+	`
+	rows, err := r.dbpool.Query(ctx, sql,
+		userId, recommendGenreWeight, recommendDirectorWeight, recommendCastWeight,
+		recommendAffinityShare, recommendLimit, movieIds, movieScores,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recommendations := []models.Recommendation{}
+	for rows.Next() {
+		var rec models.Recommendation
+		if err := rows.Scan(
+			&rec.ID,
+			&rec.Title,
+			&rec.PosterPath,
+			&rec.ReleaseDate,
+			&rec.Genres,
+			&rec.Rating.Average,
+			&rec.Rating.Count,
+			&rec.AgeRating,
+			&rec.Affinity,
+			&rec.Score,
+		); err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := localizeMovies(ctx, r.dbpool, lang, recommendations, func(rec *models.Recommendation) localized {
+		return localized{id: rec.ID, title: &rec.Title, genres: rec.Genres}
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := utils.CacheSet(r.rdb, ctx, redisKey, recommendations, recommendTTL); err != nil {
+		utils.PrintError(
+			fmt.Sprintf("redis> UNABLE TO SET %s", redisKey), 20, err,
+		)
+	}
+
+	return recommendations, nil
+}
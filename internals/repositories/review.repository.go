@@ -0,0 +1,450 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrNotVerifiedViewer = errors.New("only viewers with a paid ticket to a past showtime can review")
+	ErrReviewNotFound    = errors.New("review not found")
+	ErrOwnReview         = errors.New("you can not report your own review")
+	ErrReportLimit       = errors.New("too many reports today, try again later")
+)
+
+// reviewReportDailyLimit caps the reports a user files in a day, a report
+// hides the review until a moderator looks at it
+const reviewReportDailyLimit = 5
+
+// reviewStatus holds reviews with flagged words for a moderator
+func reviewStatus(flagged []string) string {
+	if len(flagged) > 0 {
+		return models.ReviewPending
+	}
+	return models.ReviewApproved
+}
+
+var reviewSortColumns = map[string]string{
+	"newest":  "r.created_at DESC",
+	"oldest":  "r.created_at ASC",
+	"highest": "r.rating DESC, r.created_at DESC",
+	"lowest":  "r.rating ASC, r.created_at DESC",
+}
+
+type ReviewRepository struct {
+	dbpool *pgxpool.Pool
+	rdb    *redis.Client
+}
+
+func NewReviewRepository(dbpool *pgxpool.Pool, rdb *redis.Client) *ReviewRepository {
+	return &ReviewRepository{dbpool: dbpool, rdb: rdb}
+}
+
+func (r *ReviewRepository) GetMovieReviews(ctx context.Context, movieId int, sort string, limit, offset int) ([]models.Review, int, error) {
+	var total int
+	if err := r.dbpool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM reviews r
+		JOIN movies m ON m.id = r.movie_id
+		WHERE r.movie_id = $1 AND r.status = 'approved' AND m.deleted_at IS NULL
+	`, movieId).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	orderBy, ok := reviewSortColumns[sort]
+	if !ok {
+		orderBy = reviewSortColumns["newest"]
+	}
+	sql := `
+		SELECT
+			r.id, r.movie_id, r.user_id,
+			TRIM(COALESCE(p.first_name, '') || ' ' || LEFT(COALESCE(p.last_name, ''), 1)),
+			p.avatar, r.rating, r.body, r.created_at, r.updated_at
+		FROM
+			reviews r
+		JOIN
+			movies m ON m.id = r.movie_id
+		LEFT JOIN
+			personal_info p ON p.user_id = r.user_id
+		WHERE
+			r.movie_id = $1 AND r.status = 'approved' AND m.deleted_at IS NULL
+		ORDER BY
+			` + orderBy + `, r.id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.dbpool.Query(ctx, sql, movieId, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		var review models.Review
+		if err := rows.Scan(
+			&review.ID,
+			&review.MovieID,
+			&review.UserID,
+			&review.UserName,
+			&review.Avatar,
+			&review.Rating,
+			&review.Body,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, total, rows.Err()
+}
+
+// isVerifiedViewer reports whether the user paid for a showtime of the movie
+// that has already started
+func (r *ReviewRepository) isVerifiedViewer(tx pgx.Tx, ctx context.Context, userId uint16, movieId int) (bool, error) {
+	sql := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM orders o
+			JOIN schedule s ON s.id = o.schedule_id
+			JOIN jam_tayang t ON t.id = s.time_id
+			JOIN lokasi_tayang l ON l.id = s.location_id
+			WHERE o.user_id = $1 AND s.movie_id = $2
+			AND o.paid_at IS NOT NULL AND s.cancelled_at IS NULL
+			AND ` + scheduleStartsAtExpr + ` <= NOW()
+		)
+	`
+	var verified bool
+	err := tx.QueryRow(ctx, sql, userId, movieId).Scan(&verified)
+	return verified, err
+}
+
+// refreshMovieRating recomputes the rating aggregate stored on the movie,
+// only approved reviews count
+func (r *ReviewRepository) refreshMovieRating(tx pgx.Tx, ctx context.Context, movieId int) error {
+	sql := `
+		UPDATE movies m
+		SET
+			rating_avg = COALESCE(agg.avg, 0),
+			rating_count = agg.count
+		FROM (
+			SELECT AVG(rating)::real AS avg, COUNT(*) AS count
+			FROM reviews
+			WHERE movie_id = $1 AND status = 'approved'
+		) agg
+		WHERE m.id = $1
+	`
+	_, err := tx.Exec(ctx, sql, movieId)
+	return err
+}
+
+// bustRatingCaches drops the cached lists that carry the rating
+func (r *ReviewRepository) bustRatingCaches(ctx context.Context) {
+	res, err := r.rdb.Del(ctx, upcomingsKeys()...).Result()
+	if err != nil {
+		log.Println(err)
+	}
+	log.Printf("Number of keys deleted: %d", res)
+	if err := utils.InvalidateCachePattern(r.rdb, ctx, "archie:movies_populars_*"); err != nil {
+		log.Println(err)
+	}
+}
+
+// CreateReview stores a review, flagged holds the words the profanity filter
+// found in its body
+func (r *ReviewRepository) CreateReview(ctx context.Context, userId uint16, movieId int, body models.ReviewBody, flagged []string) (int, error) {
+	tx, err := r.dbpool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var isLive bool
+	if err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1 AND deleted_at IS NULL)", movieId,
+	).Scan(&isLive); err != nil {
+		return 0, err
+	}
+	if !isLive {
+		return 0, ErrMovieNotFound
+	}
+
+	verified, err := r.isVerifiedViewer(tx, ctx, userId, movieId)
+	if err != nil {
+		return 0, err
+	}
+	if !verified {
+		return 0, ErrNotVerifiedViewer
+	}
+
+	if flagged == nil {
+		flagged = []string{}
+	}
+	var reviewId int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO reviews (movie_id, user_id, rating, body, status, flagged_words)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, movieId, userId, body.Rating, strings.TrimSpace(body.Body), reviewStatus(flagged), flagged).Scan(&reviewId); err != nil {
+		return 0, err
+	}
+	if err := r.refreshMovieRating(tx, ctx, movieId); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	r.bustRatingCaches(ctx)
+	return reviewId, nil
+}
+
+// UpdateReview edits a review, a new body goes through moderation again
+// unless it is clean and the review was already approved
+func (r *ReviewRepository) UpdateReview(ctx context.Context, userId uint16, movieId, reviewId int, body models.UpdateReviewBody, flagged []string) error {
+	var setClauses []string
+	args := []any{reviewId, movieId, userId}
+	if body.Rating != nil {
+		args = append(args, *body.Rating)
+		setClauses = append(setClauses, fmt.Sprintf("rating = $%d", len(args)))
+	}
+	if body.Body != nil {
+		args = append(args, strings.TrimSpace(*body.Body))
+		setClauses = append(setClauses, fmt.Sprintf("body = $%d", len(args)))
+		if flagged == nil {
+			flagged = []string{}
+		}
+		args = append(args, flagged)
+		setClauses = append(setClauses,
+			fmt.Sprintf("flagged_words = $%d", len(args)),
+			fmt.Sprintf("status = CASE WHEN CARDINALITY($%d::text[]) > 0 OR status <> 'approved' THEN 'pending' ELSE 'approved' END", len(args)),
+		)
+	}
+	setClauses = append(setClauses, "updated_at = now()")
+
+	tx, err := r.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := fmt.Sprintf(
+		"UPDATE reviews SET %s WHERE id = $1 AND movie_id = $2 AND user_id = $3",
+		strings.Join(setClauses, ", "),
+	)
+	ctag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if ctag.RowsAffected() == 0 {
+		return ErrReviewNotFound
+	}
+	if err := r.refreshMovieRating(tx, ctx, movieId); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.bustRatingCaches(ctx)
+	return nil
+}
+
+func (r *ReviewRepository) DeleteReview(ctx context.Context, userId uint16, movieId, reviewId int) error {
+	tx, err := r.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ctag, err := tx.Exec(ctx,
+		"DELETE FROM reviews WHERE id = $1 AND movie_id = $2 AND user_id = $3",
+		reviewId, movieId, userId,
+	)
+	if err != nil {
+		return err
+	}
+	if ctag.RowsAffected() == 0 {
+		return ErrReviewNotFound
+	}
+	if err := r.refreshMovieRating(tx, ctx, movieId); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.bustRatingCaches(ctx)
+	return nil
+}
+
+// GetModerationQueue lists reviews by status, oldest first, with the words
+// that got them flagged and their unresolved reports
+func (r *ReviewRepository) GetModerationQueue(ctx context.Context, status string, limit, offset int) ([]models.ModerationReview, int, error) {
+	var total int
+	if err := r.dbpool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM reviews WHERE status = $1", status,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql := `
+		SELECT
+			r.id, r.movie_id, r.user_id,
+			TRIM(COALESCE(p.first_name, '') || ' ' || LEFT(COALESCE(p.last_name, ''), 1)),
+			p.avatar, r.rating, r.body, r.created_at, r.updated_at,
+			m.title, r.status, r.flagged_words,
+			COALESCE((
+				SELECT JSON_AGG(JSON_BUILD_OBJECT(
+					'user_id', rr.user_id, 'reason', rr.reason, 'created_at', rr.created_at
+				) ORDER BY rr.created_at)
+				FROM review_reports rr
+				WHERE rr.review_id = r.id AND rr.resolved_at IS NULL
+			), '[]')
+		FROM
+			reviews r
+		JOIN
+			movies m ON m.id = r.movie_id
+		LEFT JOIN
+			personal_info p ON p.user_id = r.user_id
+		WHERE
+			r.status = $1
+		ORDER BY
+			r.updated_at ASC, r.id ASC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.dbpool.Query(ctx, sql, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reviews := []models.ModerationReview{}
+	for rows.Next() {
+		var review models.ModerationReview
+		if err := rows.Scan(
+			&review.ID,
+			&review.MovieID,
+			&review.UserID,
+			&review.UserName,
+			&review.Avatar,
+			&review.Rating,
+			&review.Body,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+			&review.MovieTitle,
+			&review.Status,
+			&review.FlaggedWords,
+			&review.Reports,
+		); err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, total, rows.Err()
+}
+
+// ModerateReview approves or rejects a review and resolves its reports
+func (r *ReviewRepository) ModerateReview(ctx context.Context, adminId uint16, reviewId int, status string) error {
+	tx, err := r.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var movieId int
+	if err := tx.QueryRow(ctx, `
+		UPDATE reviews
+		SET status = $1, moderated_at = now(), moderated_by = $2
+		WHERE id = $3
+		RETURNING movie_id
+	`, status, adminId, reviewId).Scan(&movieId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReviewNotFound
+		}
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE review_reports SET resolved_at = now() WHERE review_id = $1 AND resolved_at IS NULL",
+		reviewId,
+	); err != nil {
+		return err
+	}
+	if err := r.refreshMovieRating(tx, ctx, movieId); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.bustRatingCaches(ctx)
+	return nil
+}
+
+// ReportReview files a user's report and sends the review back to the queue
+func (r *ReviewRepository) ReportReview(ctx context.Context, userId uint16, movieId, reviewId int, reason string) error {
+	tx, err := r.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var authorId uint16
+	if err := tx.QueryRow(ctx, `
+		SELECT user_id FROM reviews
+		WHERE id = $1 AND movie_id = $2 AND status <> 'rejected'
+		FOR UPDATE
+	`, reviewId, movieId).Scan(&authorId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReviewNotFound
+		}
+		return err
+	}
+	if authorId == userId {
+		return ErrOwnReview
+	}
+
+	var reportsToday int
+	if err := tx.QueryRow(ctx,
+		"SELECT COUNT(*) FROM review_reports WHERE user_id = $1 AND created_at > now() - INTERVAL '1 day'",
+		userId,
+	).Scan(&reportsToday); err != nil {
+		return err
+	}
+	if reportsToday >= reviewReportDailyLimit {
+		return ErrReportLimit
+	}
+
+	if _, err := tx.Exec(ctx,
+		"INSERT INTO review_reports (review_id, user_id, reason) VALUES ($1, $2, $3)",
+		reviewId, userId, strings.TrimSpace(reason),
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE reviews SET status = 'pending' WHERE id = $1 AND status = 'approved'",
+		reviewId,
+	); err != nil {
+		return err
+	}
+	if err := r.refreshMovieRating(tx, ctx, movieId); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.bustRatingCaches(ctx)
+	return nil
+}
@@ -0,0 +1,336 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// the suggestion index keeps one sorted set per language and prefix, scored by
+// popularity, members are "kind|id|label". every entry, "lang|kind|id",
+// remembers the prefix sets it was added to so it can be removed again when
+// the movie changes
+const (
+	suggestPrefixKey  = "archie:suggest:"
+	suggestEntryKey   = "archie:suggest_entry:"
+	suggestMembersKey = "archie:suggest_members"
+	suggestRebuildKey = "archie:suggest_rebuild|"
+	suggestMaxPrefix  = 20
+	suggestLimit      = 10
+)
+
+// suggestSources lists the rows indexed for each kind and language of $1, %s
+// is an extra condition on the kind's id. movies are labelled with their
+// translated title and scored by the popularity of /movies/popular, $2 and $3,
+// people by their most popular live movie
+var suggestSources = map[string]string{
+	"movie": `
+		SELECT m.id, lang.code, COALESCE(tr.title, m.title), COALESCE(p.score, 0)
+		FROM movies m
+		LEFT JOIN UNNEST($2::int[], $3::float8[]) AS p(movie_id, score) ON p.movie_id = m.id
+		CROSS JOIN UNNEST($1::text[]) AS lang(code)
+		LEFT JOIN movie_translations tr ON tr.movie_id = m.id AND tr.language = lang.code
+		WHERE m.deleted_at IS NULL %s
+	`,
+	"director": `
+		SELECT d.id, lang.code, d.name, MAX(COALESCE(p.score, 0))
+		FROM directors d
+		JOIN movies m ON m.director_id = d.id AND m.deleted_at IS NULL
+		LEFT JOIN UNNEST($2::int[], $3::float8[]) AS p(movie_id, score) ON p.movie_id = m.id
+		CROSS JOIN UNNEST($1::text[]) AS lang(code)
+		WHERE TRUE %s
+		GROUP BY d.id, d.name, lang.code
+	`,
+	"cast": `
+		SELECT c.id, lang.code, c.name, MAX(COALESCE(p.score, 0))
+		FROM casts c
+		JOIN movies_casts mc ON mc.cast_id = c.id
+		JOIN movies m ON m.id = mc.movie_id AND m.deleted_at IS NULL
+		LEFT JOIN UNNEST($2::int[], $3::float8[]) AS p(movie_id, score) ON p.movie_id = m.id
+		CROSS JOIN UNNEST($1::text[]) AS lang(code)
+		WHERE TRUE %s
+		GROUP BY c.id, c.name, lang.code
+	`,
+}
+
+var suggestIDColumns = map[string]string{
+	"movie":    "m.id",
+	"director": "d.id",
+	"cast":     "c.id",
+}
+
+func normalizeSuggestion(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// suggestPrefixes returns the prefixes of every word-start of label so
+// "fic" also completes "Pulp Fiction"
+func suggestPrefixes(label string) []string {
+	words := strings.Fields(normalizeSuggestion(label))
+	seen := map[string]bool{}
+	var prefixes []string
+	for i := range words {
+		runes := []rune(strings.Join(words[i:], " "))
+		for n := 1; n <= len(runes) && n <= suggestMaxPrefix; n++ {
+			prefix := string(runes[:n])
+			if !seen[prefix] {
+				seen[prefix] = true
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	return prefixes
+}
+
+// indexSuggestion queues an entry into the index, into prefixes the keys
+// written while the entry sets keep naming the live keys
+func (m *MovieRepository) indexSuggestion(ctx context.Context, pipe redis.Pipeliner, into, lang, kind string, id int, label string, score float64) {
+	entry := fmt.Sprintf("%s|%s|%d", lang, kind, id)
+	member := fmt.Sprintf("%s|%d|%s", kind, id, label)
+
+	for _, prefix := range suggestPrefixes(label) {
+		key := suggestPrefixKey + lang + ":" + prefix
+		pipe.ZAdd(ctx, into+key, redis.Z{Score: score, Member: member})
+		pipe.SAdd(ctx, into+suggestEntryKey+entry, key)
+	}
+	pipe.HSet(ctx, into+suggestMembersKey, entry, member)
+}
+
+func (m *MovieRepository) removeSuggestion(ctx context.Context, kind string, id int) error {
+	pipe := m.rdb.TxPipeline()
+	for _, lang := range utils.Languages {
+		entry := fmt.Sprintf("%s|%s|%d", lang, kind, id)
+
+		member, err := m.rdb.HGet(ctx, suggestMembersKey, entry).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		keys, err := m.rdb.SMembers(ctx, suggestEntryKey+entry).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			pipe.ZRem(ctx, key, member)
+		}
+		pipe.Del(ctx, suggestEntryKey+entry)
+		pipe.HDel(ctx, suggestMembersKey, entry)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// indexSuggestionSource queues every live row of kind, limited to ids when given
+func (m *MovieRepository) indexSuggestionSource(ctx context.Context, pipe redis.Pipeliner, into, kind string, ids []int) (map[int]bool, error) {
+	scores, err := windowedPopularity(ctx, m.rdb, PopularityDefaultWindow)
+	if err != nil {
+		return nil, err
+	}
+	movieIds, movieScores := splitScores(scores)
+
+	cond, args := "", []any{utils.Languages, movieIds, movieScores}
+	if ids != nil {
+		cond = fmt.Sprintf("AND %s = ANY($4)", suggestIDColumns[kind])
+		args = append(args, ids)
+	}
+
+	rows, err := m.dbpool.Query(ctx, fmt.Sprintf(suggestSources[kind], cond), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexed := map[int]bool{}
+	for rows.Next() {
+		var (
+			id    int
+			lang  string
+			label string
+			score float64
+		)
+		if err := rows.Scan(&id, &lang, &label, &score); err != nil {
+			return nil, err
+		}
+		m.indexSuggestion(ctx, pipe, into, lang, kind, id, label, score)
+		indexed[id] = true
+	}
+	return indexed, rows.Err()
+}
+
+// RebuildSuggestIndex fills the suggestion index again from the live
+// catalogue, it is run once on startup before serving. the index is built
+// under suggestRebuildKey and renamed over the live keys, a change indexed
+// meanwhile would be overwritten
+func (m *MovieRepository) RebuildSuggestIndex(ctx context.Context) error {
+	stale, err := m.scanKeys(ctx, "archie:suggest*")
+	if err != nil {
+		return err
+	}
+
+	build := m.rdb.Pipeline()
+	for _, key := range stale {
+		// a rebuild cut short may have left its keys behind
+		if strings.HasPrefix(key, suggestRebuildKey) {
+			build.Del(ctx, key)
+		}
+	}
+	for kind := range suggestSources {
+		if _, err := m.indexSuggestionSource(ctx, build, suggestRebuildKey, kind, nil); err != nil {
+			return err
+		}
+	}
+	if _, err := build.Exec(ctx); err != nil {
+		return err
+	}
+
+	rebuilt, err := m.scanKeys(ctx, suggestRebuildKey+"*")
+	if err != nil {
+		return err
+	}
+	isRebuilt := map[string]bool{}
+	for _, key := range rebuilt {
+		isRebuilt[strings.TrimPrefix(key, suggestRebuildKey)] = true
+	}
+
+	swap := m.rdb.TxPipeline()
+	for _, key := range stale {
+		if !strings.HasPrefix(key, suggestRebuildKey) && !isRebuilt[key] {
+			swap.Del(ctx, key)
+		}
+	}
+	for _, key := range rebuilt {
+		swap.Rename(ctx, key, strings.TrimPrefix(key, suggestRebuildKey))
+	}
+	_, err = swap.Exec(ctx)
+	return err
+}
+
+// scanKeys lists the keys matching pattern
+func (m *MovieRepository) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := m.rdb.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// movieSuggestionPeople returns the director and cast ids linked to a movie
+func (m *MovieRepository) movieSuggestionPeople(ctx context.Context, movieID int) (map[string][]int, error) {
+	sql := `
+		SELECT 'director', director_id FROM movies WHERE id = $1 AND director_id IS NOT NULL
+		UNION
+		SELECT 'cast', cast_id FROM movies_casts WHERE movie_id = $1
+	`
+	rows, err := m.dbpool.Query(ctx, sql, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	people := map[string][]int{}
+	for rows.Next() {
+		var (
+			kind string
+			id   int
+		)
+		if err := rows.Scan(&kind, &id); err != nil {
+			return nil, err
+		}
+		people[kind] = append(people[kind], id)
+	}
+	return people, rows.Err()
+}
+
+// syncMovieSuggestions re-indexes a movie and its people after a change,
+// stale holds the people linked before the change so unlinked ones are
+// re-scored or dropped as well
+func (m *MovieRepository) syncMovieSuggestions(ctx context.Context, movieID int, stale map[string][]int) error {
+	people, err := m.movieSuggestionPeople(ctx, movieID)
+	if err != nil {
+		return err
+	}
+	targets := map[string][]int{"movie": {movieID}}
+	for _, set := range []map[string][]int{people, stale} {
+		for kind, ids := range set {
+			targets[kind] = append(targets[kind], ids...)
+		}
+	}
+	return m.reindexSuggestions(ctx, targets)
+}
+
+// reindexSuggestions drops the entries of targets and indexes those still
+// live again, with their current label and score
+func (m *MovieRepository) reindexSuggestions(ctx context.Context, targets map[string][]int) error {
+	for kind, ids := range targets {
+		for _, id := range ids {
+			if err := m.removeSuggestion(ctx, kind, id); err != nil {
+				return err
+			}
+		}
+
+		pipe := m.rdb.Pipeline()
+		if _, err := m.indexSuggestionSource(ctx, pipe, "", kind, ids); err != nil {
+			return err
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSuggestions completes q from the index of lang
+func (m *MovieRepository) GetSuggestions(ctx context.Context, q, lang string) ([]models.Suggestion, error) {
+	suggestions := []models.Suggestion{}
+
+	q = normalizeSuggestion(q)
+	if q == "" {
+		return suggestions, nil
+	}
+
+	// queries longer than the indexed prefixes are narrowed down in go
+	prefix, fetch := q, int64(suggestLimit)
+	if runes := []rune(q); len(runes) > suggestMaxPrefix {
+		prefix, fetch = string(runes[:suggestMaxPrefix]), 100
+	}
+
+	members, err := m.rdb.ZRevRangeWithScores(ctx, suggestPrefixKey+lang+":"+prefix, 0, fetch-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, z := range members {
+		parts := strings.SplitN(z.Member.(string), "|", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if prefix != q && !strings.Contains(normalizeSuggestion(parts[2]), q) {
+			continue
+		}
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+
+		suggestions = append(suggestions, models.Suggestion{
+			Type:  parts[0],
+			ID:    id,
+			Label: parts[2],
+			Score: z.Score,
+		})
+		if len(suggestions) == suggestLimit {
+			break
+		}
+	}
+
+	return suggestions, nil
+}
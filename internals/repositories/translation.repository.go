@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/metgag/koda-weekly10/internals/models"
+	"github.com/metgag/koda-weekly10/internals/utils"
+)
+
+var ErrTranslationNotFound = errors.New("translation not found")
+
+// localized points at the translatable fields of a listed movie, overview
+// and genres are nil for lists without them
+type localized struct {
+	id       uint32
+	title    *string
+	overview *string
+	genres   []models.Genre
+}
+
+// localizeMovies puts the lang translations over the titles, overviews and
+// genre names of items in two queries whatever their count. fields without
+// a translation keep the default language
+func localizeMovies[T any](ctx context.Context, dbpool *pgxpool.Pool, lang string, items []T, fields func(*T) localized) error {
+	if lang == utils.DefaultLanguage || len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]uint32, 0, len(items))
+	for i := range items {
+		ids = append(ids, fields(&items[i]).id)
+	}
+	rows, err := dbpool.Query(ctx, `
+		SELECT movie_id, title, overview
+		FROM movie_translations
+		WHERE language = $1 AND movie_id = ANY($2)
+	`, lang, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type translation struct{ title, overview *string }
+	translations := map[uint32]translation{}
+	for rows.Next() {
+		var (
+			movieId uint32
+			t       translation
+		)
+		if err := rows.Scan(&movieId, &t.title, &t.overview); err != nil {
+			return err
+		}
+		translations[movieId] = t
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	genreNames, err := genreTranslations(ctx, dbpool, lang)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		f := fields(&items[i])
+		if t, ok := translations[f.id]; ok {
+			if t.title != nil {
+				*f.title = *t.title
+			}
+			if t.overview != nil && f.overview != nil {
+				*f.overview = *t.overview
+			}
+		}
+		localizeGenres(f.genres, genreNames)
+	}
+	return nil
+}
+
+// genreTranslations maps the genres translated into lang to their names
+func genreTranslations(ctx context.Context, dbpool *pgxpool.Pool, lang string) (map[uint32]string, error) {
+	rows, err := dbpool.Query(ctx, "SELECT genre_id, name FROM genre_translations WHERE language = $1", lang)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := map[uint32]string{}
+	for rows.Next() {
+		var (
+			genreId uint32
+			name    string
+		)
+		if err := rows.Scan(&genreId, &name); err != nil {
+			return nil, err
+		}
+		names[genreId] = name
+	}
+	return names, rows.Err()
+}
+
+func localizeGenres(genres []models.Genre, names map[uint32]string) {
+	for i := range genres {
+		if name, ok := names[genres[i].ID]; ok {
+			genres[i].Name = name
+		}
+	}
+}
+
+func movieFilterFields(m *models.MovieFilter) localized {
+	return localized{m.ID, &m.Title, &m.Overview, m.Genres}
+}
+
+func collectionMovieFields(m *models.CollectionMovie) localized {
+	return localized{id: m.ID, title: &m.Title}
+}
+
+func (m *MovieRepository) GetMovieTranslations(ctx context.Context, movieId int) ([]models.MovieTranslation, error) {
+	var exists bool
+	if err := m.dbpool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1 AND deleted_at IS NULL)", movieId,
+	).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrMovieNotFound
+	}
+
+	rows, err := m.dbpool.Query(ctx, `
+		SELECT language, title, overview, updated_at
+		FROM movie_translations
+		WHERE movie_id = $1
+		ORDER BY language ASC
+	`, movieId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []models.MovieTranslation{}
+	for rows.Next() {
+		var t models.MovieTranslation
+		if err := rows.Scan(&t.Language, &t.Title, &t.Overview, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	return translations, rows.Err()
+}
+
+// SetMovieTranslation adds or updates the lang translation of a movie, the
+// translated title is searchable and suggested like the original
+func (m *MovieRepository) SetMovieTranslation(ctx context.Context, movieId int, lang string, body models.MovieTranslationBody) error {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+		INSERT INTO movie_translations (movie_id, language, title, overview)
+		SELECT id, $2, $3, $4 FROM movies WHERE id = $1 AND deleted_at IS NULL
+		ON CONFLICT (movie_id, language) DO UPDATE SET
+			title = COALESCE(EXCLUDED.title, movie_translations.title),
+			overview = COALESCE(EXCLUDED.overview, movie_translations.overview),
+			updated_at = now()
+	`
+	ctag, err := tx.Exec(ctx, sql, movieId, lang, body.Title, body.Overview)
+	if err != nil {
+		return err
+	}
+	if ctag.RowsAffected() == 0 {
+		return ErrMovieNotFound
+	}
+	if err := m.refreshSearchDocument(tx, ctx, uint32(movieId)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	m.bustMovieListCaches(ctx)
+	if err := m.reindexSuggestions(ctx, map[string][]int{"movie": {movieId}}); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+func (m *MovieRepository) DeleteMovieTranslation(ctx context.Context, movieId int, lang string) error {
+	tx, err := m.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ctag, err := tx.Exec(ctx,
+		"DELETE FROM movie_translations WHERE movie_id = $1 AND language = $2", movieId, lang,
+	)
+	if err != nil {
+		return err
+	}
+	if ctag.RowsAffected() == 0 {
+		return ErrTranslationNotFound
+	}
+	if err := m.refreshSearchDocument(tx, ctx, uint32(movieId)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	m.bustMovieListCaches(ctx)
+	if err := m.reindexSuggestions(ctx, map[string][]int{"movie": {movieId}}); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+func (m *MovieRepository) GetGenreTranslations(ctx context.Context, genreId int) ([]models.GenreTranslation, error) {
+	var exists bool
+	if err := m.dbpool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM genres WHERE id = $1)", genreId,
+	).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrGenreNotFound
+	}
+
+	rows, err := m.dbpool.Query(ctx,
+		"SELECT language, name FROM genre_translations WHERE genre_id = $1 ORDER BY language ASC", genreId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	translations, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.GenreTranslation])
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (m *MovieRepository) SetGenreTranslation(ctx context.Context, genreId int, lang, name string) error {
+	sql := `
+		INSERT INTO genre_translations (genre_id, language, name)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (genre_id, language) DO UPDATE SET name = EXCLUDED.name
+	`
+	if _, err := m.dbpool.Exec(ctx, sql, genreId, lang, name); err != nil {
+		if utils.IsPgError(err, utils.PgForeignKeyViolation) {
+			return ErrGenreNotFound
+		}
+		return err
+	}
+
+	m.bustMovieListCaches(ctx)
+	return nil
+}
+
+func (m *MovieRepository) DeleteGenreTranslation(ctx context.Context, genreId int, lang string) error {
+	ctag, err := m.dbpool.Exec(ctx,
+		"DELETE FROM genre_translations WHERE genre_id = $1 AND language = $2", genreId, lang,
+	)
+	if err != nil {
+		return err
+	}
+	if ctag.RowsAffected() == 0 {
+		return ErrTranslationNotFound
+	}
+
+	m.bustMovieListCaches(ctx)
+	return nil
+}
@@ -27,7 +27,7 @@ func NewUserRepository(dbpool *pgxpool.Pool) *UserRepository {
 func (u *UserRepository) GetUserinf(ctx context.Context, id uint16) (models.UserInf, error) {
 	sql := `
 		SELECT
-			user_id, first_name, last_name, phone_number, point_count, avatar
+			user_id, first_name, last_name, phone_number, point_count, avatar, birthdate
 		FROM
 			personal_info
 		WHERE
@@ -42,6 +42,7 @@ func (u *UserRepository) GetUserinf(ctx context.Context, id uint16) (models.User
 		&userinf.PhoneNumber,
 		&userinf.PointCount,
 		&userinf.Avatar,
+		&userinf.Birthdate,
 	); err != nil {
 		return models.UserInf{}, err
 	}
@@ -108,7 +109,8 @@ func (u *UserRepository) UpdateUserinf(newUserInf models.NewInf, ctx context.Con
 func (u *UserRepository) GetUserOrderHistory(ctx context.Context, id uint16) (models.UserOrder, error) {
 	query := `
 		SELECT
-			b.id "order_id", u.id "user_id", m.title, s.show_date, t.show_time, ct.cinema_img, b.paid_at
+			b.id "order_id", u.id "user_id", m.title, s.show_date, t.show_time, ct.cinema_img, b.paid_at,
+			` + scheduleStartsAtExpr + `, l.timezone, s.cancelled_at
 		FROM
 			orders AS b
 		JOIN
@@ -121,6 +123,8 @@ func (u *UserRepository) GetUserOrderHistory(ctx context.Context, id uint16) (mo
 			jam_tayang AS t ON s.time_id = t.id
 		JOIN
 			cinema_tayang AS ct ON s.cinema_id = ct.id
+		JOIN
+			lokasi_tayang AS l ON s.location_id = l.id
 		WHERE
 			u.id = $1
 		ORDER BY b.id DESC
@@ -135,6 +139,8 @@ func (u *UserRepository) GetUserOrderHistory(ctx context.Context, id uint16) (mo
 	for rows.Next() {
 		var history models.OrderHistory
 		var paidAt sql.NullTime
+		var startsAt time.Time
+		var timezone string
 
 		if err := rows.Scan(
 			&history.OrderID,
@@ -144,9 +150,13 @@ func (u *UserRepository) GetUserOrderHistory(ctx context.Context, id uint16) (mo
 			&history.Time,
 			&history.CinemaName,
 			&paidAt,
+			&startsAt,
+			&timezone,
+			&history.CancelledAt,
 		); err != nil {
 			return models.UserOrder{}, err
 		}
+		history.LocalTime = models.NewLocalTime(startsAt, timezone)
 
 		if paidAt.Valid {
 			history.PaidAt = &paidAt.Time
@@ -271,3 +281,61 @@ func (u *UserRepository) fixUpdateAt(ctx context.Context, tx pgx.Tx, id uint16)
 	`
 	return tx.Exec(ctx, sql, id)
 }
+
+func (u *UserRepository) GetUserNotifications(ctx context.Context, id uint16, limit, offset int) ([]models.Notification, int, error) {
+	var total int
+	if err := u.dbpool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM notifications WHERE user_id = $1", id,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql := `
+		SELECT
+			id, kind, order_id, movie_id, title, message, created_at, read_at
+		FROM
+			notifications
+		WHERE
+			user_id = $1
+		ORDER BY
+			created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := u.dbpool.Query(ctx, sql, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(
+			&n.ID,
+			&n.Kind,
+			&n.OrderID,
+			&n.MovieID,
+			&n.Title,
+			&n.Message,
+			&n.CreatedAt,
+			&n.ReadAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, total, rows.Err()
+}
+
+func (u *UserRepository) MarkNotificationsRead(ctx context.Context, id uint16) (pgconn.CommandTag, error) {
+	sql := `
+		UPDATE
+			notifications
+		SET
+			read_at = now()
+		WHERE
+			user_id = $1 AND read_at IS NULL
+	`
+	return u.dbpool.Exec(ctx, sql, id)
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/metgag/koda-weekly10/internals/models"
+)
+
+type WatchlistRepository struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewWatchlistRepository(dbpool *pgxpool.Pool) *WatchlistRepository {
+	return &WatchlistRepository{dbpool: dbpool}
+}
+
+// AddToWatchlist saves a live movie for the user, saving it twice is a no-op
+func (w *WatchlistRepository) AddToWatchlist(ctx context.Context, userId uint16, movieId int) (pgconn.CommandTag, error) {
+	var isLive bool
+	if err := w.dbpool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1 AND deleted_at IS NULL)", movieId,
+	).Scan(&isLive); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	if !isLive {
+		return pgconn.CommandTag{}, ErrMovieNotFound
+	}
+
+	sql := `
+		INSERT INTO
+			watchlists (user_id, movie_id)
+		VALUES
+			($1, $2)
+		ON CONFLICT
+			(user_id, movie_id)
+		DO NOTHING
+	`
+	return w.dbpool.Exec(ctx, sql, userId, movieId)
+}
+
+func (w *WatchlistRepository) RemoveFromWatchlist(ctx context.Context, userId uint16, movieId int) (pgconn.CommandTag, error) {
+	return w.dbpool.Exec(ctx,
+		"DELETE FROM watchlists WHERE user_id = $1 AND movie_id = $2", userId, movieId,
+	)
+}
+
+// GetWatchlist returns the user's saved movies, latest first. trashed movies
+// are kept in the table in case they are restored but not listed
+func (w *WatchlistRepository) GetWatchlist(ctx context.Context, userId uint16, limit, offset int, lang string) ([]models.WatchlistMovie, int, error) {
+	var total int
+	if err := w.dbpool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM watchlists wl
+		JOIN movies m ON m.id = wl.movie_id
+		WHERE wl.user_id = $1 AND m.deleted_at IS NULL
+	`, userId).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql := `
+		SELECT
+			m.id, m.title, m.poster_path, m.release_date, m.age_rating,
+			` + movieGenresJSON + `,
+			EXISTS (
+				SELECT 1
+				FROM schedule s
+				JOIN jam_tayang t ON t.id = s.time_id
+				JOIN lokasi_tayang l ON l.id = s.location_id
+				WHERE s.movie_id = m.id AND ` + liveScheduleCond + ` AND ` + upcomingScheduleCond + `
+			),
+			wl.created_at
+		FROM
+			watchlists wl
+		JOIN
+			movies m ON m.id = wl.movie_id
+		WHERE
+			wl.user_id = $1 AND m.deleted_at IS NULL
+		ORDER BY
+			wl.created_at DESC, m.id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := w.dbpool.Query(ctx, sql, userId, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movies := []models.WatchlistMovie{}
+	for rows.Next() {
+		var movie models.WatchlistMovie
+		if err := rows.Scan(
+			&movie.ID,
+			&movie.Title,
+			&movie.PosterPath,
+			&movie.ReleaseDate,
+			&movie.AgeRating,
+			&movie.Genres,
+			&movie.TicketsOpen,
+			&movie.AddedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := localizeMovies(ctx, w.dbpool, lang, movies, func(movie *models.WatchlistMovie) localized {
+		return localized{id: movie.ID, title: &movie.Title, genres: movie.Genres}
+	}); err != nil {
+		return nil, 0, err
+	}
+	return movies, total, nil
+}
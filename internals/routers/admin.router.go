@@ -3,19 +3,29 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/metgag/koda-weekly10/internals/configs"
 	"github.com/metgag/koda-weekly10/internals/handlers"
 	"github.com/metgag/koda-weekly10/internals/middlewares"
 	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/pkg"
 	"github.com/redis/go-redis/v9"
 )
 
-func InitAdminRouter(router *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client) {
-	or := repositories.NewOrderRepository(dbpool)
+func InitAdminRouter(router *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client, profanity *pkg.ProfanityFilter) {
+	or := repositories.NewOrderRepository(dbpool, rdb)
 	oh := handlers.NewOrderHandler(or)
 
 	mr := repositories.NewMovieRepository(dbpool, rdb)
 	mh := handlers.NewMovieHandler(mr)
 
+	mdh := handlers.NewMetadataHandler(mr, configs.InitMetadataProvider())
+
+	rr := repositories.NewReviewRepository(dbpool, rdb)
+	rh := handlers.NewReviewHandler(rr, profanity)
+
+	cr := repositories.NewCinemaRepository(dbpool, rdb)
+	ch := handlers.NewCinemaHandler(cr)
+
 	adminGroup := router.Group("/admin")
 	adminGroup.Use(
 		middlewares.ValidateToken(rdb),
@@ -27,12 +37,73 @@ func InitAdminRouter(router *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client
 		middlewares.Access("admin"),
 		oh.HandleGetOrderHistory,
 	)
+	adminGroup.POST("/orders", oh.HandleCreateBoxOfficeOrder)
 
 	movieGroup := adminGroup.Group("/movies")
 	{
 		movieGroup.GET("/", mh.HandleGetAllMovie)
+		movieGroup.GET("/trash", mh.HandleGetTrashedMovies)
+		movieGroup.POST("/restore", mh.HandleRestoreMovies)
+		movieGroup.POST("/purge", mh.HandlePurgeMovies)
+		movieGroup.POST("/import", mh.HandleImportMovies)
+		movieGroup.GET("/lookup", mdh.HandleLookupMovies)
+		movieGroup.POST("/from-provider/:external_id", mdh.HandleCreateMovieFromProvider)
+		movieGroup.POST("/:id/restore", mh.HandleRestoreMovies)
+		movieGroup.DELETE("/:id/purge", mh.HandlePurgeMovies)
+		movieGroup.POST("/:id/media", mh.HandleAddMovieMedia)
+		movieGroup.PATCH("/:id/media/order", mh.HandleReorderMovieMedia)
+		movieGroup.PATCH("/:id/media/:media_id/primary", mh.HandleSetPrimaryMovieMedia)
+		movieGroup.DELETE("/:id/media/:media_id", mh.HandleDeleteMovieMedia)
+		movieGroup.GET("/:id/translations", mh.HandleGetMovieTranslations)
+		movieGroup.PUT("/:id/translations/:lang", mh.HandleSetMovieTranslation)
+		movieGroup.DELETE("/:id/translations/:lang", mh.HandleDeleteMovieTranslation)
 		movieGroup.DELETE("/:id", mh.HandleDeleteMovie)
 		movieGroup.PATCH("/:id", mh.HandleMovieUpdate)
 		movieGroup.POST("/", mh.HandleCreateMovie)
 	}
+
+	genreGroup := adminGroup.Group("/genres")
+	{
+		genreGroup.POST("", mh.HandleCreateGenre)
+		genreGroup.PATCH("/:id", mh.HandleRenameGenre)
+		genreGroup.DELETE("/:id", mh.HandleDeleteGenre)
+		genreGroup.GET("/:id/translations", mh.HandleGetGenreTranslations)
+		genreGroup.PUT("/:id/translations/:lang", mh.HandleSetGenreTranslation)
+		genreGroup.DELETE("/:id/translations/:lang", mh.HandleDeleteGenreTranslation)
+	}
+
+	// directors and casts share their handlers, kind picks the table
+	for path, kind := range map[string]string{"/directors": "director", "/casts": "cast"} {
+		peopleGroup := adminGroup.Group(path)
+		peopleGroup.GET("", mh.HandleGetPeople(kind))
+		peopleGroup.POST("", mh.HandleCreatePerson(kind))
+		peopleGroup.PATCH("/:id", mh.HandleRenamePerson(kind))
+		peopleGroup.DELETE("/:id", mh.HandleDeletePerson(kind))
+		peopleGroup.POST("/:id/merge", mh.HandleMergePeople(kind))
+	}
+
+	collectionGroup := adminGroup.Group("/collections")
+	{
+		collectionGroup.GET("", mh.HandleGetCollections)
+		collectionGroup.POST("", mh.HandleCreateCollection)
+		collectionGroup.PATCH("/:id", mh.HandleUpdateCollection)
+		collectionGroup.DELETE("/:id", mh.HandleDeleteCollection)
+		collectionGroup.PUT("/:id/movies", mh.HandleSetCollectionMovies)
+	}
+
+	scheduleGroup := adminGroup.Group("/schedules")
+	{
+		scheduleGroup.POST("", mh.HandleCreateSchedules)
+		scheduleGroup.PATCH("/:id", mh.HandleUpdateSchedule)
+	}
+
+	reviewGroup := adminGroup.Group("/reviews")
+	{
+		reviewGroup.GET("", rh.HandleModerationQueue)
+		reviewGroup.POST("/:review_id/approve", rh.HandleApproveReview)
+		reviewGroup.POST("/:review_id/reject", rh.HandleRejectReview)
+	}
+
+	adminGroup.PATCH("/cinemas/:cinema_id", ch.HandleUpdateCinema)
+	adminGroup.PATCH("/locations/:location_id/timezone", ch.HandleUpdateLocationTimezone)
 }
@@ -4,22 +4,23 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/metgag/koda-weekly10/internals/handlers"
-	"github.com/metgag/koda-weekly10/internals/middlewares"
 	"github.com/metgag/koda-weekly10/internals/repositories"
 	"github.com/redis/go-redis/v9"
 )
 
 func InitCinemaRouter(router *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client) {
-	cr := repositories.NewCinemaRepository(dbpool)
+	cr := repositories.NewCinemaRepository(dbpool, rdb)
 	ch := handlers.NewCinemaHandler(cr)
 
-	cinemaRouter := router.Group("/cinemas")
-	cinemaRouter.Use(
-		middlewares.ValidateToken(rdb),
-		middlewares.Access("user", "admin"),
-	)
+	// browsing is public, only booking (POST /orders) needs a token
+	router.GET("/schedules", ch.HandlerSchedule)
+	router.GET("/schedules/formats", ch.HandleScreenFormats)
 
+	cinemaRouter := router.Group("/cinemas")
 	{
+		cinemaRouter.GET("", ch.HandleCinemas)
+		cinemaRouter.GET("/nearby", ch.HandleNearbyCinemas)
+		cinemaRouter.GET("/detail/:cinema_id", ch.HandleCinemaDetail)
 		cinemaRouter.GET("/schedules", ch.HandlerSchedule)
 		cinemaRouter.GET("/:schedule_id/seats", ch.HandlerSeats)
 		cinemaRouter.GET("/:schedule_id/selected", ch.HandlerCinemaNameAndTime)
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/metgag/koda-weekly10/internals/handlers"
+	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitCollectionRouter(router *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client) {
+	mr := repositories.NewMovieRepository(dbpool, rdb)
+	mh := handlers.NewMovieHandler(mr)
+
+	collectionRouter := router.Group("collections")
+	{
+		collectionRouter.GET("/:id", mh.HandleGetCollection)
+	}
+}
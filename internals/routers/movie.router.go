@@ -4,23 +4,47 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/metgag/koda-weekly10/internals/handlers"
+	"github.com/metgag/koda-weekly10/internals/middlewares"
 	"github.com/metgag/koda-weekly10/internals/repositories"
+	"github.com/metgag/koda-weekly10/pkg"
 	"github.com/redis/go-redis/v9"
 )
 
-func InitMovieRouter(router *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client) {
+func InitMovieRouter(router *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client, profanity *pkg.ProfanityFilter) {
 	mr := repositories.NewMovieRepository(dbpool, rdb)
 	mh := handlers.NewMovieHandler(mr)
 
+	rr := repositories.NewReviewRepository(dbpool, rdb)
+	rh := handlers.NewReviewHandler(rr, profanity)
+
 	movieRouter := router.Group("movies")
 
+	// signed in users get their watchlisted movies flagged
+	personalized := middlewares.OptionalToken(rdb)
+
 	{
-		movieRouter.GET("/upcoming", mh.GetUpcomingMovies)
-		movieRouter.GET("/popular", mh.GetPopularMovies)
-		movieRouter.GET("", mh.HandleMovieWithGenrePageSearch)
-		movieRouter.GET("/:id", mh.GetMovieDetail)
+		movieRouter.GET("/upcoming", personalized, mh.GetUpcomingMovies)
+		movieRouter.GET("/now-showing", personalized, mh.GetNowShowingMovies)
+		movieRouter.GET("/popular", personalized, mh.GetPopularMovies)
+		movieRouter.GET("", personalized, mh.HandleMovieWithGenrePageSearch)
+		movieRouter.GET("/suggest", mh.HandleMovieSuggestions)
+		movieRouter.GET("/:id", personalized, mh.GetMovieDetail)
 		movieRouter.GET("/:id/schedules", mh.HandleGetMovieSchedule)
 		movieRouter.GET("/:id/schedule", mh.HandleGetMovieScheduleFilter)
+		movieRouter.GET("/:id/showtimes", mh.HandleGetMovieShowtimes)
 		movieRouter.GET("/genres", mh.HandleGenres)
+		movieRouter.GET("/:id/reviews", rh.HandleGetMovieReviews)
+	}
+
+	reviewRouter := movieRouter.Group("/:id/reviews")
+	reviewRouter.Use(
+		middlewares.ValidateToken(rdb),
+		middlewares.Access("user"),
+	)
+	{
+		reviewRouter.POST("", rh.HandleCreateReview)
+		reviewRouter.PATCH("/:review_id", rh.HandleUpdateReview)
+		reviewRouter.DELETE("/:review_id", rh.HandleDeleteReview)
+		reviewRouter.POST("/:review_id/report", rh.HandleReportReview)
 	}
 }
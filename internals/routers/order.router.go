@@ -10,7 +10,7 @@ import (
 )
 
 func InitOrderRouter(router *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client) {
-	or := repositories.NewOrderRepository(dbpool)
+	or := repositories.NewOrderRepository(dbpool, rdb)
 	oh := handlers.NewOrderHandler(or)
 
 	router.POST("/orders",
@@ -7,11 +7,12 @@ import (
 
 	docs "github.com/metgag/koda-weekly10/docs"
 	"github.com/metgag/koda-weekly10/internals/middlewares"
+	"github.com/metgag/koda-weekly10/pkg"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func InitRouter(dbpool *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
+func InitRouter(dbpool *pgxpool.Pool, rdb *redis.Client, profanity *pkg.ProfanityFilter) *gin.Engine {
 	r := gin.Default()
 	r.Use(middlewares.CORSMiddleware)
 
@@ -21,13 +22,16 @@ func InitRouter(dbpool *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
 	r.Static("backdrop", "public/backdrop")
 	r.Static("poster", "public/poster")
 	r.Static("user", "public/user")
+	r.Static("media", "public/media")
 
 	InitAuthRouter(r, dbpool, rdb)
-	InitMovieRouter(r, dbpool, rdb)
+	InitMovieRouter(r, dbpool, rdb, profanity)
+	InitPeopleRouter(r, dbpool, rdb)
+	InitCollectionRouter(r, dbpool, rdb)
 	InitUserRouter(r, dbpool, rdb)
 	InitCinemaRouter(r, dbpool, rdb)
 	InitOrderRouter(r, dbpool, rdb)
-	InitAdminRouter(r, dbpool, rdb)
+	InitAdminRouter(r, dbpool, rdb, profanity)
 
 	return r
 }
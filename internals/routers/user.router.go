@@ -13,6 +13,12 @@ func InitUserRouter(r *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client) {
 	ur := repositories.NewUserRepository(dbpool)
 	uh := handlers.NewUserHandler(ur)
 
+	wr := repositories.NewWatchlistRepository(dbpool)
+	wh := handlers.NewWatchlistHandler(wr)
+
+	rr := repositories.NewRecommendationRepository(dbpool, rdb)
+	rh := handlers.NewRecommendationHandler(rr)
+
 	userGroup := r.Group("/users")
 	userGroup.Use(
 		middlewares.ValidateToken(rdb),
@@ -24,5 +30,11 @@ func InitUserRouter(r *gin.Engine, dbpool *pgxpool.Pool, rdb *redis.Client) {
 		userGroup.PATCH("/", uh.HandleUpdateUserInf)
 		userGroup.GET("/orders", uh.HandleUserOrderHistory)
 		userGroup.PATCH("/password", uh.HandlePasswordEdit)
+		userGroup.GET("/notifications", uh.HandleUserNotifications)
+		userGroup.PATCH("/notifications/read", uh.HandleReadNotifications)
+		userGroup.GET("/watchlist", wh.HandleGetWatchlist)
+		userGroup.POST("/watchlist/:movie_id", wh.HandleAddToWatchlist)
+		userGroup.DELETE("/watchlist/:movie_id", wh.HandleRemoveFromWatchlist)
+		userGroup.GET("/recommendations", rh.HandleGetRecommendations)
 	}
 }
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language of movie titles, overviews and genre names
+// as stored, translations hold the other Languages
+const DefaultLanguage = "en"
+
+// Languages are the languages served, DefaultLanguage first
+var Languages = []string{DefaultLanguage, "id"}
+
+var languageMatcher = language.NewMatcher([]language.Tag{language.English, language.Indonesian})
+
+// GetLanguage reads the lang query, or else the Accept-Language header,
+// falling back to DefaultLanguage when neither matches a language served
+func GetLanguage(ctx *gin.Context) string {
+	if lang := ctx.Query("lang"); lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			if _, index, confidence := languageMatcher.Match(tag); confidence != language.No {
+				return Languages[index]
+			}
+		}
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+	if _, index, confidence := languageMatcher.Match(tags...); confidence != language.No {
+		return Languages[index]
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name, query, header, want string
+	}{
+		{"nothing", "", "", "en"},
+		{"query", "?lang=id", "", "id"},
+		{"query region", "?lang=id-ID", "", "id"},
+		{"query wins", "?lang=en", "id", "en"},
+		{"unknown query", "?lang=fr", "id-ID,id;q=0.9", "id"},
+		{"invalid query", "?lang=!!", "", "en"},
+		{"header", "", "id-ID,id;q=0.9,en;q=0.8", "id"},
+		{"header weights", "", "fr;q=0.9,en;q=0.8,id;q=0.5", "en"},
+		{"unknown header", "", "fr-FR", "en"},
+		{"invalid header", "", ";;;", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/movies/suggest"+tt.query, nil)
+			if tt.header != "" {
+				ctx.Request.Header.Set("Accept-Language", tt.header)
+			}
+			if got := GetLanguage(ctx); got != tt.want {
+				t.Errorf("GetLanguage = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
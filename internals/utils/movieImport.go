@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/metgag/koda-weekly10/internals/models"
+)
+
+var ErrImportFormat = errors.New("import file must be .csv or .json")
+
+// ParseMovieImport reads catalogue rows from a CSV file with a header line or
+// a JSON array, the format is picked from the filename extension
+func ParseMovieImport(filename string, r io.Reader) ([]models.ImportMovieRow, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return parseMovieImportCSV(r)
+	case ".json":
+		return parseMovieImportJSON(r)
+	default:
+		return nil, ErrImportFormat
+	}
+}
+
+func parseMovieImportCSV(r io.Reader) ([]models.ImportMovieRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read csv header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("csv header must contain a title column")
+	}
+
+	var rows []models.ImportMovieRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		// a malformed record is reported on its line, the reader goes on
+		// with the next one
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, models.ImportMovieRow{
+				Line:       parseErr.StartLine,
+				ParseError: parseErr.Err.Error(),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		rows = append(rows, models.ImportMovieRow{
+			Line:         line,
+			Title:        field("title"),
+			Overview:     field("overview"),
+			Runtime:      field("runtime"),
+			ReleaseDate:  field("release_date"),
+			Director:     field("director"),
+			Casts:        field("casts"),
+			Genres:       field("genres"),
+			PosterPath:   field("poster_path"),
+			BackdropPath: field("backdrop_path"),
+		})
+	}
+
+	return rows, nil
+}
+
+func parseMovieImportJSON(r io.Reader) ([]models.ImportMovieRow, error) {
+	var items []map[string]any
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("json must be an array of movies: %w", err)
+	}
+
+	// numbers and arrays are accepted as well so the distributor's export
+	// does not have to be massaged into strings first
+	text := func(item map[string]any, key string) string {
+		switch v := item[key].(type) {
+		case nil:
+			return ""
+		case string:
+			return strings.TrimSpace(v)
+		case float64:
+			return fmt.Sprintf("%g", v)
+		case []any:
+			parts := make([]string, 0, len(v))
+			for _, p := range v {
+				parts = append(parts, strings.TrimSpace(fmt.Sprint(p)))
+			}
+			return strings.Join(parts, ", ")
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+
+	rows := make([]models.ImportMovieRow, 0, len(items))
+	for i, item := range items {
+		rows = append(rows, models.ImportMovieRow{
+			Line:         i + 1,
+			Title:        text(item, "title"),
+			Overview:     text(item, "overview"),
+			Runtime:      text(item, "runtime"),
+			ReleaseDate:  text(item, "release_date"),
+			Director:     text(item, "director"),
+			Casts:        text(item, "casts"),
+			Genres:       text(item, "genres"),
+			PosterPath:   text(item, "poster_path"),
+			BackdropPath: text(item, "backdrop_path"),
+		})
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMovieImportCSVReportsBadRecords(t *testing.T) {
+	in := "title,director\nAlpha,Ann\nBeta \"cut,Bo\nGamma,Gil\n\"Delta,Dee\n"
+	rows, err := ParseMovieImport("movies.csv", strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseMovieImport = %v, want the bad records as rows", err)
+	}
+
+	want := []struct {
+		line  int
+		title string
+		bad   bool
+	}{
+		{2, "Alpha", false},
+		{3, "", true},
+		{4, "Gamma", false},
+		{5, "", true},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.Line != w.line || row.Title != w.title || (row.ParseError != "") != w.bad {
+			t.Errorf("row %d = line %d %q error %q, want line %d %q bad %v",
+				i, row.Line, row.Title, row.ParseError, w.line, w.title, w.bad)
+		}
+	}
+}
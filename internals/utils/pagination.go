@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPagination reads page & limit query, falling back to defaultLimit and
+// capping the limit at maxLimit
+func GetPagination(ctx *gin.Context, defaultLimit, maxLimit int) (page, limit, offset int) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err = strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	return page, limit, (page - 1) * limit
+}
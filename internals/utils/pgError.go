@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	PgForeignKeyViolation = "23503"
+	PgUniqueViolation     = "23505"
+	PgCheckViolation      = "23514"
+)
+
+func IsPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
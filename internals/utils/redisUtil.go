@@ -47,3 +47,26 @@ func InvalidateCache(rdb *redis.Client, ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+func InvalidateCachePattern(rdb *redis.Client, ctx context.Context, pattern string) error {
+	var keys []string
+	iter := rdb.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		PrintError(
+			fmt.Sprintf("redis> ERROR SCAN %s", pattern), 20, nil)
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := rdb.Del(ctx, keys...).Err(); err != nil {
+		PrintError(
+			fmt.Sprintf("redis> ERROR INVALIDATE %s", pattern), 20, nil)
+		return err
+	}
+	return nil
+}
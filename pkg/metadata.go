@@ -0,0 +1,184 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrMetadataNotFound = errors.New("metadata not found")
+
+type MetadataCandidate struct {
+	ExternalID  string `json:"external_id" example:"680"`
+	Title       string `json:"title" example:"Pulp Fiction"`
+	ReleaseDate string `json:"release_date" example:"1994-09-10"`
+	Overview    string `json:"overview"`
+	PosterURL   string `json:"poster_url"`
+}
+
+type MovieMetadata struct {
+	ExternalID  string
+	Title       string
+	Overview    string
+	Runtime     uint16
+	ReleaseDate string
+	PosterURL   string
+	BackdropURL string
+	Popularity  float32
+	Director    string
+	Casts       []string
+	Genres      []string
+}
+
+// MetadataProvider looks movies up in an external catalogue
+type MetadataProvider interface {
+	Name() string
+	Search(ctx context.Context, query string) ([]MetadataCandidate, error)
+	Movie(ctx context.Context, externalID string) (MovieMetadata, error)
+}
+
+// TMDBProvider talks to the TMDB v3 API, or anything serving the same
+// routes, e.g. a local fixture server
+type TMDBProvider struct {
+	baseURL  string
+	imageURL string
+	apiKey   string
+	client   *http.Client
+	maxCasts int
+}
+
+func NewTMDBProvider(baseURL, imageURL, apiKey string) *TMDBProvider {
+	return &TMDBProvider{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		imageURL: strings.TrimRight(imageURL, "/"),
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		maxCasts: 10,
+	}
+}
+
+func (t *TMDBProvider) Name() string {
+	return "tmdb"
+}
+
+func (t *TMDBProvider) get(ctx context.Context, path string, params url.Values, result any) error {
+	if t.apiKey != "" {
+		params.Set("api_key", t.apiKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrMetadataNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("tmdb> %s %s", path, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+func (t *TMDBProvider) image(path string) string {
+	if path == "" {
+		return ""
+	}
+	return t.imageURL + path
+}
+
+func (t *TMDBProvider) Search(ctx context.Context, query string) ([]MetadataCandidate, error) {
+	var body struct {
+		Results []struct {
+			ID          int    `json:"id"`
+			Title       string `json:"title"`
+			ReleaseDate string `json:"release_date"`
+			Overview    string `json:"overview"`
+			PosterPath  string `json:"poster_path"`
+		} `json:"results"`
+	}
+	if err := t.get(ctx, "/search/movie", url.Values{"query": {query}}, &body); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]MetadataCandidate, 0, len(body.Results))
+	for _, r := range body.Results {
+		candidates = append(candidates, MetadataCandidate{
+			ExternalID:  fmt.Sprint(r.ID),
+			Title:       r.Title,
+			ReleaseDate: r.ReleaseDate,
+			Overview:    r.Overview,
+			PosterURL:   t.image(r.PosterPath),
+		})
+	}
+	return candidates, nil
+}
+
+func (t *TMDBProvider) Movie(ctx context.Context, externalID string) (MovieMetadata, error) {
+	var body struct {
+		ID           int     `json:"id"`
+		Title        string  `json:"title"`
+		Overview     string  `json:"overview"`
+		Runtime      uint16  `json:"runtime"`
+		ReleaseDate  string  `json:"release_date"`
+		PosterPath   string  `json:"poster_path"`
+		BackdropPath string  `json:"backdrop_path"`
+		Popularity   float32 `json:"popularity"`
+		Genres       []struct {
+			Name string `json:"name"`
+		} `json:"genres"`
+		Credits struct {
+			Cast []struct {
+				Name  string `json:"name"`
+				Order int    `json:"order"`
+			} `json:"cast"`
+			Crew []struct {
+				Name string `json:"name"`
+				Job  string `json:"job"`
+			} `json:"crew"`
+		} `json:"credits"`
+	}
+	path := "/movie/" + url.PathEscape(externalID)
+	if err := t.get(ctx, path, url.Values{"append_to_response": {"credits"}}, &body); err != nil {
+		return MovieMetadata{}, err
+	}
+
+	meta := MovieMetadata{
+		ExternalID:  fmt.Sprint(body.ID),
+		Title:       body.Title,
+		Overview:    body.Overview,
+		Runtime:     body.Runtime,
+		ReleaseDate: body.ReleaseDate,
+		PosterURL:   t.image(body.PosterPath),
+		BackdropURL: t.image(body.BackdropPath),
+		Popularity:  body.Popularity,
+	}
+	for _, g := range body.Genres {
+		meta.Genres = append(meta.Genres, g.Name)
+	}
+	for _, c := range body.Credits.Crew {
+		if c.Job == "Director" {
+			meta.Director = c.Name
+			break
+		}
+	}
+	cast := body.Credits.Cast
+	sort.SliceStable(cast, func(i, j int) bool { return cast[i].Order < cast[j].Order })
+	for i := 0; i < len(cast) && i < t.maxCasts; i++ {
+		meta.Casts = append(meta.Casts, cast[i].Name)
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// default word lists, kept short on purpose: anything flagged is only held
+// for a moderator, never rejected outright
+var (
+	profanityEN = []string{
+		"fuck", "fucking", "fucker", "shit", "bullshit", "bitch", "bastard",
+		"asshole", "dick", "cunt", "motherfucker", "slut", "whore", "retard",
+	}
+	profanityID = []string{
+		"anjing", "anjir", "bangsat", "bajingan", "brengsek", "kontol", "memek",
+		"ngentot", "goblok", "tolol", "kampret", "keparat", "tai", "jancok", "asu",
+	}
+)
+
+// leet maps the usual character swaps back to letters before matching
+var leet = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+type ProfanityFilter struct {
+	words map[string]bool
+}
+
+func NewProfanityFilter(words []string) *ProfanityFilter {
+	f := &ProfanityFilter{words: map[string]bool{}}
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			f.words[w] = true
+		}
+	}
+	return f
+}
+
+// NewDefaultProfanityFilter uses the built-in English and Indonesian lists
+func NewDefaultProfanityFilter() *ProfanityFilter {
+	return NewProfanityFilter(append(append([]string{}, profanityEN...), profanityID...))
+}
+
+// ReadProfanityList reads one word per line, lines starting with # are comments
+func ReadProfanityList(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// Check returns the listed words found in text, each once
+func (f *ProfanityFilter) Check(text string) []string {
+	tokens := strings.FieldsFunc(leet.Replace(strings.ToLower(text)), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	found := []string{}
+	seen := map[string]bool{}
+	for _, token := range tokens {
+		if f.words[token] && !seen[token] {
+			seen[token] = true
+			found = append(found, token)
+		}
+	}
+	return found
+}
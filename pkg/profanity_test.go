@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestProfanityFilterCheck(t *testing.T) {
+	f := NewProfanityFilter([]string{"shit", " Bangsat ", "", "tai"})
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"clean", "a fine movie, would watch again", []string{}},
+		{"case", "What a SHIT ending", []string{"shit"}},
+		{"punctuation", "bangsat!!! the plot...", []string{"bangsat"}},
+		{"leet", "sh1t acting, b4ngs@t", []string{"shit", "bangsat"}},
+		{"each once", "shit, shit and more shit", []string{"shit"}},
+		{"whole words only", "the details are detailed", []string{}},
+		{"found in order", "tai then shit", []string{"tai", "shit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Check(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Check(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultProfanityFilter(t *testing.T) {
+	f := NewDefaultProfanityFilter()
+	if got := f.Check("fucking goblok"); !slices.Equal(got, []string{"fucking", "goblok"}) {
+		t.Errorf("default lists missed a word, got %q", got)
+	}
+}
+
+func TestReadProfanityList(t *testing.T) {
+	words, err := ReadProfanityList(strings.NewReader("# english\nshit\n\n  tai  \n#tolol\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"shit", "tai"}; !slices.Equal(words, want) {
+		t.Errorf("ReadProfanityList = %q, want %q", words, want)
+	}
+}